@@ -6,6 +6,8 @@
 package toon
 
 import (
+	"database/sql"
+	"io"
 	"time"
 
 	"github.com/toon-format/toon-go/internal/codec"
@@ -23,6 +25,38 @@ const (
 	DelimiterPipe = codec.DelimiterPipe
 )
 
+// ErrEmptyDocument is returned when WithErrorOnEmpty is enabled and the input
+// contains no content.
+var ErrEmptyDocument = codec.ErrEmptyDocument
+
+// UnsupportedTypeError is returned by Marshal when a value, or a map key,
+// has a type that cannot be represented in TOON, such as a func or chan.
+type UnsupportedTypeError = codec.UnsupportedTypeError
+
+// SyntaxError describes a problem in a TOON document at a 1-based line. The
+// decoder's parse errors have this type, and Lint returns a list of them.
+// Line is zero for problems that do not belong to a line, such as a size
+// limit.
+type SyntaxError = codec.SyntaxError
+
+// NeedsQuoting reports whether s must be quoted when emitted as a value. When
+// inArray is true, delimiter is the active array delimiter; otherwise it is the
+// document delimiter.
+func NeedsQuoting(s string, inArray bool, delimiter Delimiter) bool {
+	return codec.NeedsQuoting(s, inArray, delimiter)
+}
+
+// QuoteString escapes s and wraps it in double quotes.
+func QuoteString(s string) (string, error) {
+	return codec.QuoteString(s)
+}
+
+// EncodeKey renders key as an object key, quoting it when it is not a valid
+// unquoted identifier.
+func EncodeKey(key string) (string, error) {
+	return codec.EncodeKey(key)
+}
+
 // EncoderOption mutates encoding behaviour.
 type EncoderOption = codec.EncoderOption
 
@@ -41,6 +75,24 @@ func NewObject(fields ...Field) Object {
 	return codec.NewObject(fields...)
 }
 
+// ObjectOption mutates how NewObjectFromMap builds an Object.
+type ObjectOption = codec.ObjectOption
+
+// WithMissingKeysAsNull makes NewObjectFromMap add a null field for each key
+// in keyOrder that the map lacks, instead of failing.
+func WithMissingKeysAsNull(enabled bool) ObjectOption {
+	return codec.WithMissingKeysAsNull(enabled)
+}
+
+// NewObjectFromMap builds an ordered Object from m. Keys listed in keyOrder
+// come first, in that order; the remaining keys follow sorted by byte order.
+// Repeated keys in keyOrder are used once. A listed key missing from m is an
+// error unless WithMissingKeysAsNull is set. Nested maps are stored as they
+// are, so the encoder sorts their keys.
+func NewObjectFromMap(m map[string]any, keyOrder []string, opts ...ObjectOption) (Object, error) {
+	return codec.NewObjectFromMap(m, keyOrder, opts...)
+}
+
 // Encoder serializes Go values as TOON documents.
 type Encoder = codec.Encoder
 
@@ -51,6 +103,39 @@ func NewEncoder(opts ...EncoderOption) *Encoder {
 }
 
 // Marshal renders v into a TOON document using a temporary encoder.
+//
+// A struct field tagged `toon:"name,prec=N"` rounds its value, or each number
+// in its array, to N decimal places. Rounded numbers are still written in
+// canonical form, so 1.50 is rendered as 1.5.
+//
+// A field tagged `toon:"name,method=Get"` is encoded from the result of
+// calling its Get method, which must take no arguments and return one value.
+// This also applies to unexported fields, which are otherwise skipped; they
+// are never decoded.
+//
+// A struct whose only field is a slice or array tagged `toon:",root"` encodes
+// as that array, exactly as if the slice were marshaled directly, and
+// Unmarshal fills the field from a root array. Tagging a field root in a
+// struct with other encoded fields is an error.
+//
+// Byte slices and arrays encode as arrays of numbers by default. A field
+// tagged `toon:"digest,hex"` encodes as a lowercase hex string instead, and
+// Unmarshal decodes the string back; prefer it for hashes and identifiers,
+// where the text is far shorter and matches how such values are displayed.
+//
+// An untagged embedded interface whose dynamic value is a struct, or a pointer
+// to one, has that struct's fields promoted into the enclosing object; fields
+// of the enclosing struct take precedence on name clashes. Any other dynamic
+// value, including nil, is encoded under the interface's type name. Unmarshal
+// cannot reverse the promotion, since the concrete type is unknown: to decode
+// such a shape, give the field a tag name so it encodes as a nested object,
+// and register its concrete types with RegisterType.
+//
+// Iterators are encoded without building a slice first: an iter.Seq becomes
+// an array, and an iter.Seq2 with string keys an object whose fields follow
+// the order of iteration. Since an array header declares its length, the
+// sequence is read to the end before any output is produced; it is iterated
+// once per call, so single-use sequences can only be marshaled once.
 func Marshal(v any, opts ...EncoderOption) ([]byte, error) {
 	return codec.Marshal(v, opts...)
 }
@@ -60,6 +145,78 @@ func MarshalString(v any, opts ...EncoderOption) (string, error) {
 	return codec.MarshalString(v, opts...)
 }
 
+// MarshalRows reads the remaining rows of a query result and encodes them as
+// a root tabular array whose fields are the result's column names. NULL
+// columns become null and []byte columns strings. Reading every row closes
+// rows, as with any loop over Next; the caller still closes rows when
+// MarshalRows fails. A result without rows encodes as an empty array.
+func MarshalRows(rows *sql.Rows, opts ...EncoderOption) ([]byte, error) {
+	return codec.MarshalRows(rows, opts...)
+}
+
+// WriterEncoder writes TOON documents to an io.Writer, mirroring the
+// ergonomics of encoding/json's Encoder.
+type WriterEncoder = codec.WriterEncoder
+
+// NewWriterEncoder returns an encoder that writes to w using the supplied
+// options.
+func NewWriterEncoder(w io.Writer, opts ...EncoderOption) *WriterEncoder {
+	return codec.NewWriterEncoder(w, opts...)
+}
+
+// ReaderDecoder reads a stream of TOON documents from an io.Reader, such as
+// the output of WriterEncoder, whose documents are separated by lines holding
+// only "---". Line numbers in errors count from the start of each document.
+type ReaderDecoder = codec.ReaderDecoder
+
+// NewReaderDecoder returns a decoder that reads documents from r using the
+// supplied options. WithMaxInputBytes applies to each document and stops
+// reading one as soon as it grows past the limit.
+func NewReaderDecoder(r io.Reader, opts ...DecoderOption) *ReaderDecoder {
+	return codec.NewReaderDecoder(r, opts...)
+}
+
+// GzipEncoder writes a gzip-compressed stream of TOON documents to an
+// io.Writer; Encode behaves as on WriterEncoder. Compressed data is buffered,
+// so Close must be called once encoding is done; until then the output is
+// truncated and cannot be decompressed. Close does not close the underlying
+// writer.
+type GzipEncoder = codec.GzipEncoder
+
+// NewGzipEncoder returns an encoder that compresses its output to w with gzip
+// at the default compression level.
+func NewGzipEncoder(w io.Writer, opts ...EncoderOption) *GzipEncoder {
+	return codec.NewGzipEncoder(w, opts...)
+}
+
+// GzipDecoder reads a gzip-compressed stream of TOON documents, such as the
+// output of GzipEncoder, one document at a time as ReaderDecoder does.
+// WithMaxInputBytes bounds the decompressed size of each document, which
+// guards against highly compressed input.
+type GzipDecoder = codec.GzipDecoder
+
+// NewGzipDecoder returns a decoder that decompresses r with gzip. It fails if
+// r does not begin with a valid gzip header.
+func NewGzipDecoder(r io.Reader, opts ...DecoderOption) (*GzipDecoder, error) {
+	return codec.NewGzipDecoder(r, opts...)
+}
+
+// Stats summarizes a rendered TOON document.
+type Stats = codec.Stats
+
+// MarshalWithStats renders v like Marshal and also reports Stats for the
+// rendered document.
+func MarshalWithStats(v any, opts ...EncoderOption) ([]byte, Stats, error) {
+	return codec.MarshalWithStats(v, opts...)
+}
+
+// EstimateTokens approximates the number of LLM tokens in s using the common
+// heuristic of four characters per token. It is intended for comparing
+// formats, not for exact budgeting against a specific tokenizer.
+func EstimateTokens(s string) int {
+	return codec.EstimateTokens(s)
+}
+
 // WithIndent configures the number of spaces used per indentation level.
 func WithIndent(spaces int) EncoderOption {
 	return codec.WithIndent(spaces)
@@ -78,15 +235,229 @@ func WithArrayDelimiter(delimiter Delimiter) EncoderOption {
 }
 
 // WithLengthMarkers enables emitting optional # markers in array headers.
+// It replaces any predicate set by WithLengthMarkersFunc.
 func WithLengthMarkers(enabled bool) EncoderOption {
 	return codec.WithLengthMarkers(enabled)
 }
 
+// WithLengthMarkersFunc decides per array header whether to emit the #
+// marker. depth is the indentation level of the header line, so arrays at
+// the root of the document, or fields of the root object, have depth 0.
+// Passing nil restores the setting of WithLengthMarkers.
+func WithLengthMarkersFunc(fn func(depth int) bool) EncoderOption {
+	return codec.WithLengthMarkersFunc(fn)
+}
+
 // WithTimeFormatter specifies the formatter used for time.Time normalization.
 func WithTimeFormatter(formatter func(time.Time) string) EncoderOption {
 	return codec.WithTimeFormatter(formatter)
 }
 
+// WithTimeLocation converts time.Time values to loc before formatting, so
+// that the default formatter writes them with loc's offset, such as
+// 2025-10-31T08:00:00-04:00, instead of in UTC. The written instant is
+// unchanged and decodes back to an equal time.Time.
+func WithTimeLocation(loc *time.Location) EncoderOption {
+	return codec.WithTimeLocation(loc)
+}
+
+// WithJSONBridge makes values implementing json.Marshaler encode through
+// MarshalJSON. The bridge is consulted after the built-in handling of
+// time.Time, fmt.Stringer and Object values, but before reflection.
+func WithJSONBridge(enabled bool) EncoderOption {
+	return codec.WithJSONBridge(enabled)
+}
+
+// WithTabularFill lets arrays of uniform objects that contain nil elements,
+// such as a []*T with nil entries, keep the tabular form by emitting a row of
+// null cells for each nil. Without it such arrays fall back to list form with
+// "- null" items.
+func WithTabularFill(enabled bool) EncoderOption {
+	return codec.WithTabularFill(enabled)
+}
+
+// WithTabularFillValue sets the placeholder written in every cell of the rows
+// that WithTabularFill emits for nil elements, in place of null, so that they
+// cannot be mistaken for rows of genuine nulls. The value must be a string,
+// number or bool, or every encode fails, even of documents without nil rows;
+// nil restores null cells. Pick a placeholder that real rows never contain in
+// every cell, and decode with WithDecoderTabularFillValue to read such rows
+// back as nil.
+func WithTabularFillValue(value any) EncoderOption {
+	return codec.WithTabularFillValue(value)
+}
+
+// WithFieldFilter encodes only the object fields for which keep returns true,
+// for projecting or redacting a value at run time. keep receives the path of
+// each struct field, map entry and Object field, in the notation of Diff:
+// dotted keys with [index] suffixes for array elements, such as
+// "users[0].email". A rejected field is skipped together with everything below
+// it, so keep must accept the parents of the fields it selects. An object left
+// without fields by the filter is dropped from its parent object as well,
+// rather than encoded empty; array elements keep their place.
+func WithFieldFilter(keep func(path string) bool) EncoderOption {
+	return codec.WithFieldFilter(keep)
+}
+
+// WithSortTabularColumns sorts the fields of tabular array headers, and the
+// cells of each row, alphabetically instead of following the field order of
+// the first element. Output then no longer depends on how the rows were built.
+func WithSortTabularColumns(enabled bool) EncoderOption {
+	return codec.WithSortTabularColumns(enabled)
+}
+
+// WithDottedKeyCollapse folds chains of single-field objects into dotted keys,
+// so {"a": {"b": {"c": 1}}} encodes as "a.b.c: 1". Only keys made of plain
+// identifier segments are folded, including in the objects of list items.
+// Keys that already contain a dot are quoted, so that decoding with
+// WithDottedKeyExpansion, which this option pairs with, restores them intact.
+func WithDottedKeyCollapse(enabled bool) EncoderOption {
+	return codec.WithDottedKeyCollapse(enabled)
+}
+
+// WithTypeDiscriminator makes values whose type was registered with
+// RegisterType encode with a leading key field naming the type, so that they
+// can be decoded back into interface-typed destinations. Registered types
+// must be structs or pointers to structs; those implementing fmt.Stringer,
+// driver.Valuer or, with WithJSONBridge, json.Marshaler keep encoding through
+// that method.
+func WithTypeDiscriminator(key string) EncoderOption {
+	return codec.WithTypeDiscriminator(key)
+}
+
+// WithNullLiteral replaces the "null" token emitted for nil values, for
+// example with "~". This departs from the TOON core profile, so documents
+// must be decoded with WithDecoderNullLiteral. Literals that are empty,
+// collide with other literals, or would require quoting are ignored. Strings
+// equal to the literal are quoted.
+func WithNullLiteral(literal string) EncoderOption {
+	return codec.WithNullLiteral(literal)
+}
+
+// WithNaturalKeyOrder sorts map keys so that runs of digits compare by
+// numeric value, placing "item2" before "item10". Struct fields and Object
+// values keep their declared order regardless.
+func WithNaturalKeyOrder(enabled bool) EncoderOption {
+	return codec.WithNaturalKeyOrder(enabled)
+}
+
+// WithMapKeyComparator sorts map keys with compare, which returns a negative
+// number, zero or a positive number as a sorts before, equal to or after b.
+// Keys that compare equal fall back to lexicographic order so that output
+// stays deterministic. The comparator takes precedence over
+// WithNaturalKeyOrder; nil restores the default.
+func WithMapKeyComparator(compare func(a, b string) int) EncoderOption {
+	return codec.WithMapKeyComparator(compare)
+}
+
+// WithEmitEmptyObject makes an empty root object encode as the single line
+// "{}" instead of an empty document, for consumers that cannot handle empty
+// input. The decoder reads a bare "{}" root as an empty object.
+func WithEmitEmptyObject(enabled bool) EncoderOption {
+	return codec.WithEmitEmptyObject(enabled)
+}
+
+// WithDelimiterEscaping writes the active delimiter inside inline and tabular
+// cells as a backslash escape, such as "a\|b", instead of quoting the whole
+// cell, when the delimiter is the only reason the cell needs quoting. Decode
+// such documents with WithDecoderDelimiterEscaping.
+func WithDelimiterEscaping(enabled bool) EncoderOption {
+	return codec.WithDelimiterEscaping(enabled)
+}
+
+// WithLossless makes Marshal fail instead of silently changing a value it
+// cannot write back exactly. The coercions it turns into errors are: NaN and
+// infinite floats or json.Number values written as null, negative zero
+// written as 0, json.Number literals rewritten in canonical form (such as 1.0
+// as 1 or 1e3 as 1000), and json.Number values that are not numbers written as
+// strings. Integers beyond ±(2^53-1), which are written as strings that
+// Unmarshal reads back into integer fields, are still allowed.
+func WithLossless(enabled bool) EncoderOption {
+	return codec.WithLossless(enabled)
+}
+
+// WithDeterministic pins every setting that could make the output depend on
+// how a value was built or on configured callbacks, for content-addressed
+// artifacts. The keys of maps, structs and Object values are sorted by byte
+// order, times are written in UTC as RFC 3339 with nanoseconds, and
+// WithTimeFormatter, WithTimeLocation, WithMapKeyComparator and
+// WithNaturalKeyOrder are ignored. Numbers are always written as the shortest
+// decimal that round-trips, without exponents. The remaining options, such as
+// indentation and delimiters, still apply, so equal values encoded with
+// equal options produce identical bytes.
+func WithDeterministic(enabled bool) EncoderOption {
+	return codec.WithDeterministic(enabled)
+}
+
+// WithNumericBooleans writes true and false as the unquoted numbers 1 and 0.
+// Decode reads them back as numbers; Unmarshal restores booleans in bool
+// fields when decoding with WithBoolLiterals([]string{"1"}, []string{"0"}).
+func WithNumericBooleans(enabled bool) EncoderOption {
+	return codec.WithNumericBooleans(enabled)
+}
+
+// WithSchemaHeader prepends text to the document as comment lines, each
+// starting with "# ", for example to describe the schema to an LLM reading
+// the document. TOON has no comments, so decode such documents with
+// WithSkipSchemaHeader.
+func WithSchemaHeader(text string) EncoderOption {
+	return codec.WithSchemaHeader(text)
+}
+
+// WithMaxInlineWidth limits the length, in characters and including
+// indentation, of inline primitive arrays and tabular rows. A primitive array
+// whose line would be longer is written in list form with one "- " item per
+// value; a tabular array with any longer row is written as a list of objects.
+// The two decisions are made independently per array. A value of zero or less
+// disables the limit.
+func WithMaxInlineWidth(n int) EncoderOption {
+	return codec.WithMaxInlineWidth(n)
+}
+
+// WithAutoDelimiter chooses the delimiter of each inline and tabular array
+// among comma, tab and pipe, picking the one contained in the fewest string
+// cells so that the fewest cells need quoting. Ties keep the delimiter set by
+// WithArrayDelimiter.
+func WithAutoDelimiter(enabled bool) EncoderOption {
+	return codec.WithAutoDelimiter(enabled)
+}
+
+// WithTabularThreshold writes an array of objects with primitive values in
+// tabular form when at least ratio of its objects carry every field found
+// across the array, rather than only when all of them share the same fields.
+// The header lists the fields in first-seen order, and a field that an object
+// lacks is written as a null cell, so it decodes as null rather than absent.
+// A ratio of zero or less keeps the default of exact uniformity.
+func WithTabularThreshold(ratio float64) EncoderOption {
+	return codec.WithTabularThreshold(ratio)
+}
+
+// Profile names a bundle of encoder and decoder options for a common use case.
+type Profile = codec.Profile
+
+const (
+	// ProfileStrict emits length markers on every array header. The decoder
+	// side decodes in strict mode and rejects empty documents, unknown struct
+	// fields and fractional numbers for integer fields.
+	ProfileStrict = codec.ProfileStrict
+	// ProfileCompact minimizes output size: no length markers, the delimiter
+	// needing the fewest quotes for each array, and tabular rows for arrays in
+	// which at least half of the objects carry every field. A field that such
+	// an object lacks is written as null, which Unmarshal only accepts into
+	// pointer and interface fields. Otherwise its output reads back with the
+	// default decoder options, which is what the decoder side applies.
+	ProfileCompact = codec.ProfileCompact
+	// ProfileVerbose favours readability: length markers and four-space
+	// indentation.
+	ProfileVerbose = codec.ProfileVerbose
+)
+
+// WithProfile applies the encoder options bundled by profile. Options passed
+// after it override the profile's choices. Unknown profiles are ignored.
+func WithProfile(profile Profile) EncoderOption {
+	return codec.WithProfile(profile)
+}
+
 // Decoder parses TOON documents into Go values that match the data model from
 // Section 2. Numbers are returned as float64, objects as map[string]any, and
 // arrays as []any. Strings are unescaped per Section 7.1.
@@ -107,6 +478,35 @@ func DecodeString(s string, opts ...DecoderOption) (any, error) {
 	return codec.DecodeString(s, opts...)
 }
 
+// DecodeFragment decodes a document whose lines all share a common leading
+// indentation, such as a subtree cut out of a larger document. The smallest
+// indentation among non-blank lines is removed before decoding; line numbers
+// in errors still refer to data.
+func DecodeFragment(data []byte, opts ...DecoderOption) (any, error) {
+	return codec.DecodeFragment(data, opts...)
+}
+
+// Lint checks data as strict decoding does and reports every problem it finds
+// instead of stopping at the first, for linters and CI checks. Problems the
+// parser can continue past, such as length mismatches, are recorded as they
+// occur, and duplicate keys are reported too. Any other error skips the
+// offending line and the lines nested below it before parsing resumes. Lint
+// returns nil for a valid document.
+func Lint(data []byte, opts ...DecoderOption) []SyntaxError {
+	return codec.Lint(data, opts...)
+}
+
+// Span locates a decoded object or array in its source document. Lines are
+// 1-based and inclusive; EndLine is the last non-blank line of the node.
+// Obtain spans with Decoder.DecodeSpans.
+type Span = codec.Span
+
+// ElementIterator lazily decodes the elements of a document whose root is an
+// array. Elements are produced one at a time as the iteration advances, so
+// large arrays never need to be collected into a single slice. Obtain one with
+// Decoder.Elements.
+type ElementIterator = codec.ElementIterator
+
 // WithStrictMode toggles the strict-mode diagnostics.
 func WithStrictMode(strict bool) DecoderOption {
 	return codec.WithStrictMode(strict)
@@ -117,15 +517,265 @@ func WithDecoderIndent(spaces int) DecoderOption {
 	return codec.WithDecoderIndent(spaces)
 }
 
+// WithAutoIndent makes the decoder take the indentation step from the first
+// indented line of each document instead of from WithDecoderIndent, so
+// documents written with any consistent step decode without configuration.
+// Strict mode still requires every line to be a multiple of that step.
+func WithAutoIndent(enabled bool) DecoderOption {
+	return codec.WithAutoIndent(enabled)
+}
+
+// WithTabIndentation makes the decoder treat each leading tab as one
+// indentation level instead of rejecting tabs. Strict mode rejects lines that
+// mix tabs and spaces in their indentation; permissive mode counts the spaces
+// against the indentation step as usual.
+func WithTabIndentation(enabled bool) DecoderOption {
+	return codec.WithTabIndentation(enabled)
+}
+
 // WithDecoderDocumentDelimiter configures the delimiter that influences
 // delimiter-aware string parsing when no array header is active.
 func WithDecoderDocumentDelimiter(delimiter Delimiter) DecoderOption {
 	return codec.WithDecoderDocumentDelimiter(delimiter)
 }
 
+// WithDecoderJSONBridge makes Unmarshal populate destinations implementing
+// json.Unmarshaler by re-encoding the decoded subtree as JSON and calling
+// UnmarshalJSON.
+func WithDecoderJSONBridge(enabled bool) DecoderOption {
+	return codec.WithDecoderJSONBridge(enabled)
+}
+
+// WithErrorOnEmpty makes Decode and Unmarshal return ErrEmptyDocument for input
+// that is empty or whitespace-only instead of an empty object.
+func WithErrorOnEmpty(enabled bool) DecoderOption {
+	return codec.WithErrorOnEmpty(enabled)
+}
+
+// WithMaxInputBytes rejects documents larger than n bytes before parsing
+// begins. ReaderDecoder and GzipDecoder apply the limit to each document of
+// the stream, measured after decompression, and stop reading a document as
+// soon as it grows past n bytes. A value of zero or less disables the limit.
+func WithMaxInputBytes(n int) DecoderOption {
+	return codec.WithMaxInputBytes(n)
+}
+
+// WithMaxLineBytes rejects documents containing a line longer than n bytes,
+// excluding its line terminator, so a single pathological line is reported
+// with its line number. A value of zero or less disables the limit.
+func WithMaxLineBytes(n int) DecoderOption {
+	return codec.WithMaxLineBytes(n)
+}
+
+// WithMaxElements caps the total number of array elements, including tabular
+// rows, produced while decoding a document. A value of zero or less disables
+// the limit.
+func WithMaxElements(n int) DecoderOption {
+	return codec.WithMaxElements(n)
+}
+
+// WithDottedKeyExpansion expands unquoted dotted keys such as "a.b.c: 1" into
+// nested objects. Quoted keys stay literal. When an expanded path meets an
+// object already decoded at the same key, the two are merged; any other
+// collision is an error in strict mode, and the later value wins otherwise.
+func WithDottedKeyExpansion(enabled bool) DecoderOption {
+	return codec.WithDottedKeyExpansion(enabled)
+}
+
+// WithDecoderTypeDiscriminator sets the field consulted when decoding an
+// object into an interface-typed destination. The field's value selects a
+// type registered with RegisterType. The default key is "_type".
+func WithDecoderTypeDiscriminator(key string) DecoderOption {
+	return codec.WithDecoderTypeDiscriminator(key)
+}
+
+// WithDecoderNullLiteral makes the decoder read the bare token literal as null
+// in addition to "null". Invalid literals are ignored, as with
+// WithNullLiteral.
+func WithDecoderNullLiteral(literal string) DecoderOption {
+	return codec.WithDecoderNullLiteral(literal)
+}
+
+// WithDecoderTabularFillValue makes the decoder read a tabular row whose cells
+// all equal value, the placeholder given to WithTabularFillValue, as a null
+// element. Numbers are matched by their canonical form. Nil disables the
+// mapping.
+func WithDecoderTabularFillValue(value any) DecoderOption {
+	return codec.WithDecoderTabularFillValue(value)
+}
+
+// WithCaseInsensitiveLiterals makes a permissive decoder read true, false and
+// null in any letter case, such as True, FALSE or Null, for ingesting
+// documents from producers that capitalize them. Strict mode ignores the
+// option and matches only the lowercase literals the specification defines.
+// Marshal does not quote strings such as "True", so enabling the option on
+// documents produced by this package can turn such strings into booleans.
+func WithCaseInsensitiveLiterals(enabled bool) DecoderOption {
+	return codec.WithCaseInsensitiveLiterals(enabled)
+}
+
+// WithAllowNonFinite makes the decoder read the unquoted tokens NaN, Infinity
+// and -Infinity as the corresponding float64 values instead of strings, for
+// ingesting documents from producers that emit them. The option is
+// deliberately asymmetric: Marshal writes non-finite floats as null, and does
+// not quote a string such as "NaN", so enabling it on documents produced by
+// this package can turn such strings into numbers.
+func WithAllowNonFinite(enabled bool) DecoderOption {
+	return codec.WithAllowNonFinite(enabled)
+}
+
+// WithExtendedNumbers makes the decoder read unquoted integers with a 0x, 0o or
+// 0b prefix, such as 0xFF or -0b1010, as numbers instead of strings. The
+// encoder never writes these forms, and it leaves strings such as "0xFF"
+// unquoted, so enable the option only for producers that mean them as
+// numbers.
+func WithExtendedNumbers(enabled bool) DecoderOption {
+	return codec.WithExtendedNumbers(enabled)
+}
+
+// WithUseNumber makes Decode return numbers as json.Number values holding
+// their source text instead of float64, so literals such as 10.50 or
+// 12345678901234567890 keep their exact form. Marshal writes such values back
+// with all their digits, in canonical form. Unmarshal is unaffected.
+func WithUseNumber(enabled bool) DecoderOption {
+	return codec.WithUseNumber(enabled)
+}
+
+// WithStrictIntegerSources makes Unmarshal reject numbers written with a
+// fraction or exponent, such as 42.0 or 4.2e1, when the destination is an
+// integer, instead of accepting any integral value. This surfaces fields
+// whose producers have started emitting fractional numbers.
+func WithStrictIntegerSources(enabled bool) DecoderOption {
+	return codec.WithStrictIntegerSources(enabled)
+}
+
+// WithDelimiterFallback salvages documents whose array headers declare a tab
+// or pipe delimiter while the values are separated by commas. In permissive
+// mode, an inline array or tabular row that does not split into the declared
+// number of values with the declared delimiter is split by commas when that
+// yields the declared number instead. Strict mode ignores the option and
+// reports the mismatch.
+func WithDelimiterFallback(enabled bool) DecoderOption {
+	return codec.WithDelimiterFallback(enabled)
+}
+
+// WithRootKey makes Unmarshal decode the value stored under key in a root
+// object, instead of the whole document, into its target. A document of the
+// form "items[2]: ..." can then fill a []Item without a wrapper struct. The
+// other keys are ignored, and a missing key or a root that is not an object
+// is an error. Decode is unaffected.
+func WithRootKey(key string) DecoderOption {
+	return codec.WithRootKey(key)
+}
+
+// WithOrderedObjects makes Decode return objects as Object values, which keep
+// the document's key order, instead of as map[string]any. Re-encoding such a
+// value reproduces the original key order.
+func WithOrderedObjects(enabled bool) DecoderOption {
+	return codec.WithOrderedObjects(enabled)
+}
+
+// WithSkipSchemaHeader makes the decoder ignore the lines starting with "#"
+// that precede the document, such as those written by WithSchemaHeader. Only
+// leading lines are skipped; a "#" line after the first content line is
+// parsed as usual.
+func WithSkipSchemaHeader(enabled bool) DecoderOption {
+	return codec.WithSkipSchemaHeader(enabled)
+}
+
+// WithDecoderDelimiterEscaping makes the decoder read a backslash followed by
+// the active delimiter, outside quotes in inline and tabular cells, as a
+// literal delimiter, matching documents written with WithDelimiterEscaping.
+func WithDecoderDelimiterEscaping(enabled bool) DecoderOption {
+	return codec.WithDecoderDelimiterEscaping(enabled)
+}
+
+// WithBoolLiterals makes the decoder read the unquoted tokens in trueSet as
+// true and those in falseSet as false, for producers that write yes/no or
+// on/off. Tokens listed in both sets, the keywords true, false and null, and
+// tokens starting with a quote are ignored, and quoted strings are never
+// converted. Tokens that look numeric, such as 1 and 0, still decode as
+// numbers; Unmarshal converts them only when the destination is a bool. Each
+// call replaces the previous sets.
+func WithBoolLiterals(trueSet, falseSet []string) DecoderOption {
+	return codec.WithBoolLiterals(trueSet, falseSet)
+}
+
+// WithForbiddenKeys makes decoding fail when any of keys appears as an object
+// key or tabular field name anywhere in the document, whatever the
+// destination type, as a guard for untrusted input. With
+// WithDottedKeyExpansion each segment of a dotted key is checked as well.
+// Each call replaces the previous set.
+func WithForbiddenKeys(keys ...string) DecoderOption {
+	return codec.WithForbiddenKeys(keys...)
+}
+
+// WithUnknownFieldHandler registers fn to be called for every object key that
+// does not match a field of the destination struct during Unmarshal. path
+// locates the containing object, using dots for keys and [i] for indices, and
+// is empty at the root. The handler runs before WithDisallowUnknownFields
+// reports its error.
+func WithUnknownFieldHandler(fn func(path, key string, value any)) DecoderOption {
+	return codec.WithUnknownFieldHandler(fn)
+}
+
+// WithDisallowUnknownFields makes Unmarshal fail when an object contains a key
+// that does not match a field of the destination struct.
+func WithDisallowUnknownFields(disallow bool) DecoderOption {
+	return codec.WithDisallowUnknownFields(disallow)
+}
+
+// RegisterType associates name with the dynamic type of value so that objects
+// carrying the discriminator field can be decoded into interface-typed
+// destinations, including slice elements and map values such as
+// map[string]Shape. Registering a pointer value instantiates pointers. It
+// panics if name is empty or already bound to a different type, or if the
+// type is already registered under a different name.
+func RegisterType(name string, value any) {
+	codec.RegisterType(name, value)
+}
+
+// LineKind classifies a line of a TOON document.
+type LineKind = codec.LineKind
+
+const (
+	// LineBlank is an empty or whitespace-only line.
+	LineBlank = codec.LineBlank
+	// LineHeader declares an array, such as "items[2]:" or "[3]{a,b}:".
+	LineHeader = codec.LineHeader
+	// LineKeyValue is a "key: value" pair or a "key:" object opener.
+	LineKeyValue = codec.LineKeyValue
+	// LineListItem starts with the "- " list-item marker.
+	LineListItem = codec.LineListItem
+	// LineValue is a bare value such as a tabular row or a root primitive.
+	LineValue = codec.LineValue
+)
+
+// Line is a single classified line of a TOON document.
+type Line = codec.Line
+
+// Tokenize splits data into classified lines without building values. The
+// indentation rules of the supplied decoder options apply.
+func Tokenize(data []byte, opts ...DecoderOption) ([]Line, error) {
+	return codec.Tokenize(data, opts...)
+}
+
+// ParseHeader parses line as an array header and reports its key, declared
+// length, delimiter and tabular field names. Surrounding whitespace and a
+// leading "- " list-item marker are ignored, so the Content of a Line from
+// Tokenize can be passed directly. ok is false when line is not a header;
+// err is set when it looks like one but is malformed. The key is empty for
+// root and list-item arrays.
+func ParseHeader(line string) (key string, length int, delim Delimiter, fields []string, ok bool, err error) {
+	return codec.ParseHeader(line)
+}
+
 // Unmarshal decodes the TOON document in data into v, which must be a non-nil
 // pointer. Struct fields use `toon` struct tags for naming and omitempty
-// semantics, mirroring Marshal behaviour.
+// semantics, mirroring Marshal behaviour. A `toonalias` tag lists further
+// comma-separated keys accepted for a field, which Marshal ignores in favour
+// of the primary name. Object destinations receive fields in document order,
+// with nested objects also decoded as Object.
 func Unmarshal(data []byte, v any, opts ...DecoderOption) error {
 	return codec.Unmarshal(data, v, opts...)
 }
@@ -134,3 +784,156 @@ func Unmarshal(data []byte, v any, opts ...DecoderOption) error {
 func UnmarshalString(s string, v any, opts ...DecoderOption) error {
 	return codec.UnmarshalString(s, v, opts...)
 }
+
+// WithDecoderProfile applies the decoder options bundled by profile, matching
+// documents produced with WithProfile. Options passed after it override the
+// profile's choices. Unknown profiles are ignored.
+func WithDecoderProfile(profile Profile) DecoderOption {
+	return codec.WithDecoderProfile(profile)
+}
+
+// Options gathers the encoder settings in a struct, for applications that load
+// configuration from a file. Zero-valued fields keep the encoder defaults.
+// Functional options remain the canonical API; Options is converted into them
+// by EncoderOptions.
+type Options = codec.Options
+
+// DecoderOptions gathers the decoder settings in a struct. Zero-valued fields
+// keep the decoder defaults, which is why strict mode is expressed as its
+// inverse, Lenient.
+type DecoderOptions = codec.DecoderOptions
+
+// MarshalWith encodes v using the settings in opts.
+func MarshalWith(v any, opts Options) ([]byte, error) {
+	return codec.MarshalWith(v, opts)
+}
+
+// DecodeWith decodes data using the settings in opts.
+func DecodeWith(data []byte, opts DecoderOptions) (any, error) {
+	return codec.DecodeWith(data, opts)
+}
+
+// UnmarshalWith decodes data into v using the settings in opts.
+func UnmarshalWith(data []byte, v any, opts DecoderOptions) error {
+	return codec.UnmarshalWith(data, v, opts)
+}
+
+// ArrayMergeStrategy selects how Merge combines two arrays found at the same
+// path.
+type ArrayMergeStrategy = codec.ArrayMergeStrategy
+
+const (
+	// ArrayMergeReplace keeps the override array and discards the base array.
+	ArrayMergeReplace = codec.ArrayMergeReplace
+	// ArrayMergeAppend concatenates the override array onto the base array.
+	ArrayMergeAppend = codec.ArrayMergeAppend
+)
+
+// MergeOption mutates merge behaviour.
+type MergeOption = codec.MergeOption
+
+// WithArrayMerge selects how arrays present in both documents are combined.
+// The default is ArrayMergeReplace.
+func WithArrayMerge(strategy ArrayMergeStrategy) MergeOption {
+	return codec.WithArrayMerge(strategy)
+}
+
+// WithMergeDecoderOptions configures how both input documents are decoded.
+func WithMergeDecoderOptions(opts ...DecoderOption) MergeOption {
+	return codec.WithMergeDecoderOptions(opts...)
+}
+
+// WithMergeEncoderOptions configures how the merged document is encoded.
+func WithMergeEncoderOptions(opts ...EncoderOption) MergeOption {
+	return codec.WithMergeEncoderOptions(opts...)
+}
+
+// Merge deep-merges the TOON document override onto base and returns the
+// result as TOON. Objects are merged key by key: keys keep their position in
+// base, and keys only present in override follow in override order. Arrays
+// are combined according to WithArrayMerge. In every other case, including
+// type mismatches such as an object meeting a scalar, the override value
+// replaces the base value; a null in override therefore sets the value to
+// null rather than removing the key. Numbers keep their source text, so large
+// integers and decimals such as 1.10 are written back unchanged.
+func Merge(base, override []byte, opts ...MergeOption) ([]byte, error) {
+	return codec.Merge(base, override, opts...)
+}
+
+// ChangeKind classifies a Change reported by Diff.
+type ChangeKind = codec.ChangeKind
+
+const (
+	// ChangeAdded marks a path present only in the second document.
+	ChangeAdded = codec.ChangeAdded
+	// ChangeRemoved marks a path present only in the first document.
+	ChangeRemoved = codec.ChangeRemoved
+	// ChangeModified marks a path whose value differs between the documents.
+	ChangeModified = codec.ChangeModified
+)
+
+// Change describes one difference found by Diff. Path uses the notation of
+// decode errors: dotted keys with [index] suffixes, and "" for the root. Old
+// and New hold the values as Decode would return them; Old is nil for
+// additions and New is nil for removals, so Kind distinguishes those from an
+// explicit null.
+type Change = codec.Change
+
+// Diff decodes the TOON documents a and b and reports how b differs from a.
+// Objects are compared key by key and arrays index by index; when the values
+// at a path have different types the whole value is reported as modified.
+// Changes follow the key order of a, with additions listed after the keys
+// both objects share. Numbers compare by value, so 1.0 and 1 are equal.
+func Diff(a, b []byte, opts ...DecoderOption) ([]Change, error) {
+	return codec.Diff(a, b, opts...)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical form of the TOON
+// document in data, so that documents with the same content share a
+// fingerprint. The canonical form is the document decoded and re-encoded with
+// the default encoder options: object keys sorted, two-space indentation and
+// comma delimiters. Numbers are compared by their exact decimal value as
+// written, so 1.0 and 1 match while integers beyond 2^53 that differ only past
+// float64 precision do not. Indentation, key order, delimiter choice and
+// quoting style therefore do not affect the result, while array order does.
+// opts describe how to parse data; options that only change the shape of
+// decoded values, such as WithOrderedObjects and WithUseNumber, are ignored.
+func Fingerprint(data []byte, opts ...DecoderOption) ([32]byte, error) {
+	return codec.Fingerprint(data, opts...)
+}
+
+// DecodeRows decodes a document whose root is an array of objects, such as a
+// tabular array, and returns its rows as maps. It fails when the root is not
+// an array or any element is not an object. WithOrderedObjects is ignored, so
+// rows and the objects inside them are always maps.
+func DecodeRows(data []byte, opts ...DecoderOption) ([]map[string]any, error) {
+	return codec.DecodeRows(data, opts...)
+}
+
+// DecodeRowsAt is like DecodeRows for the array stored under key in a
+// document whose root is an object.
+func DecodeRowsAt(data []byte, key string, opts ...DecoderOption) ([]map[string]any, error) {
+	return codec.DecodeRowsAt(data, key, opts...)
+}
+
+// Get decodes data and returns the value at path, which uses the notation of
+// Diff: dotted keys with [index] suffixes, such as users[0].name, and "" for
+// the root. It fails when a key is missing, an index is out of range, or a
+// segment meets a value of the wrong type. Keys containing dots or brackets
+// cannot be addressed. With WithOrderedObjects, objects are walked and
+// returned as Object values.
+func Get(data []byte, path string, opts ...DecoderOption) (any, error) {
+	return codec.Get(data, path, opts...)
+}
+
+// Set decodes data, stores value at path and returns the document re-encoded
+// with the default encoder options. Paths use the notation of Get. Objects
+// keep their key order, and a new key is appended to its object. Missing or
+// null values met by a key segment are replaced by new objects, so
+// intermediate objects are created as needed. An index equal to the array
+// length appends value; larger indices are an error, as is a segment meeting
+// a value of the wrong type. The empty path replaces the whole document.
+// Numbers elsewhere in the document keep their source text.
+func Set(data []byte, path string, value any, opts ...DecoderOption) ([]byte, error) {
+	return codec.Set(data, path, value, opts...)
+}
@@ -0,0 +1,152 @@
+package codec
+
+import "time"
+
+// Options gathers the encoder settings in a struct, for applications that load
+// configuration from a file. Zero-valued fields keep the encoder defaults.
+// Functional options remain the canonical API; Options is converted into them
+// by EncoderOptions.
+type Options struct {
+	Indent             int
+	Delimiter          Delimiter
+	DocumentDelimiter  Delimiter
+	LengthMarkers      bool
+	LengthMarkersFunc  func(depth int) bool
+	TimeFormatter      func(time.Time) string
+	TimeLocation       *time.Location
+	JSONBridge         bool
+	TabularFill        bool
+	TabularFillValue   any
+	SortTabularColumns bool
+	DottedKeyCollapse  bool
+	TypeDiscriminator  string
+	NullLiteral        string
+	NaturalKeyOrder    bool
+	MapKeyComparator   func(a, b string) int
+	EmitEmptyObject    bool
+	DelimiterEscaping  bool
+	MaxInlineWidth     int
+	Lossless           bool
+	Deterministic      bool
+	NumericBooleans    bool
+	SchemaHeader       string
+	FieldFilter        func(path string) bool
+	AutoDelimiter      bool
+	TabularThreshold   float64
+}
+
+// EncoderOptions converts o into the equivalent functional options.
+func (o Options) EncoderOptions() []EncoderOption {
+	return []EncoderOption{
+		WithIndent(o.Indent),
+		WithArrayDelimiter(o.Delimiter),
+		WithDocumentDelimiter(o.DocumentDelimiter),
+		WithLengthMarkers(o.LengthMarkers),
+		WithLengthMarkersFunc(o.LengthMarkersFunc),
+		WithTimeFormatter(o.TimeFormatter),
+		WithTimeLocation(o.TimeLocation),
+		WithJSONBridge(o.JSONBridge),
+		WithTabularFill(o.TabularFill),
+		WithTabularFillValue(o.TabularFillValue),
+		WithSortTabularColumns(o.SortTabularColumns),
+		WithDottedKeyCollapse(o.DottedKeyCollapse),
+		WithTypeDiscriminator(o.TypeDiscriminator),
+		WithNullLiteral(o.NullLiteral),
+		WithNaturalKeyOrder(o.NaturalKeyOrder),
+		WithMapKeyComparator(o.MapKeyComparator),
+		WithEmitEmptyObject(o.EmitEmptyObject),
+		WithDelimiterEscaping(o.DelimiterEscaping),
+		WithMaxInlineWidth(o.MaxInlineWidth),
+		WithLossless(o.Lossless),
+		WithDeterministic(o.Deterministic),
+		WithNumericBooleans(o.NumericBooleans),
+		WithSchemaHeader(o.SchemaHeader),
+		WithFieldFilter(o.FieldFilter),
+		WithAutoDelimiter(o.AutoDelimiter),
+		WithTabularThreshold(o.TabularThreshold),
+	}
+}
+
+// DecoderOptions gathers the decoder settings in a struct. Zero-valued fields
+// keep the decoder defaults, which is why strict mode is expressed as its
+// inverse, Lenient.
+type DecoderOptions struct {
+	Indent                  int
+	AutoIndent              bool
+	TabIndentation          bool
+	DocumentDelimiter       Delimiter
+	Lenient                 bool
+	JSONBridge              bool
+	ErrorOnEmpty            bool
+	MaxInputBytes           int
+	MaxLineBytes            int
+	MaxElements             int
+	DottedKeyExpansion      bool
+	TypeDiscriminator       string
+	NullLiteral             string
+	TabularFillValue        any
+	AllowNonFinite          bool
+	CaseInsensitiveLiterals bool
+	ExtendedNumbers         bool
+	UseNumber               bool
+	StrictIntegerSources    bool
+	DelimiterFallback       bool
+	RootKey                 string
+	OrderedObjects          bool
+	SkipSchemaHeader        bool
+	DelimiterEscaping       bool
+	ForbiddenKeys           []string
+	TrueLiterals            []string
+	FalseLiterals           []string
+	UnknownFieldHandler     func(path, key string, value any)
+	DisallowUnknownFields   bool
+}
+
+// DecoderOptions converts o into the equivalent functional options.
+func (o DecoderOptions) DecoderOptions() []DecoderOption {
+	return []DecoderOption{
+		WithDecoderIndent(o.Indent),
+		WithAutoIndent(o.AutoIndent),
+		WithTabIndentation(o.TabIndentation),
+		WithDecoderDocumentDelimiter(o.DocumentDelimiter),
+		WithStrictMode(!o.Lenient),
+		WithDecoderJSONBridge(o.JSONBridge),
+		WithErrorOnEmpty(o.ErrorOnEmpty),
+		WithMaxInputBytes(o.MaxInputBytes),
+		WithMaxLineBytes(o.MaxLineBytes),
+		WithMaxElements(o.MaxElements),
+		WithDottedKeyExpansion(o.DottedKeyExpansion),
+		WithDecoderTypeDiscriminator(o.TypeDiscriminator),
+		WithDecoderNullLiteral(o.NullLiteral),
+		WithDecoderTabularFillValue(o.TabularFillValue),
+		WithAllowNonFinite(o.AllowNonFinite),
+		WithCaseInsensitiveLiterals(o.CaseInsensitiveLiterals),
+		WithExtendedNumbers(o.ExtendedNumbers),
+		WithUseNumber(o.UseNumber),
+		WithStrictIntegerSources(o.StrictIntegerSources),
+		WithDelimiterFallback(o.DelimiterFallback),
+		WithRootKey(o.RootKey),
+		WithOrderedObjects(o.OrderedObjects),
+		WithSkipSchemaHeader(o.SkipSchemaHeader),
+		WithDecoderDelimiterEscaping(o.DelimiterEscaping),
+		WithForbiddenKeys(o.ForbiddenKeys...),
+		WithBoolLiterals(o.TrueLiterals, o.FalseLiterals),
+		WithUnknownFieldHandler(o.UnknownFieldHandler),
+		WithDisallowUnknownFields(o.DisallowUnknownFields),
+	}
+}
+
+// MarshalWith encodes v using the settings in opts.
+func MarshalWith(v any, opts Options) ([]byte, error) {
+	return Marshal(v, opts.EncoderOptions()...)
+}
+
+// DecodeWith decodes data using the settings in opts.
+func DecodeWith(data []byte, opts DecoderOptions) (any, error) {
+	return Decode(data, opts.DecoderOptions()...)
+}
+
+// UnmarshalWith decodes data into v using the settings in opts.
+func UnmarshalWith(data []byte, v any, opts DecoderOptions) error {
+	return Unmarshal(data, v, opts.DecoderOptions()...)
+}
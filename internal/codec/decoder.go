@@ -3,6 +3,8 @@ package codec
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 	"unicode"
@@ -29,14 +31,36 @@ func NewDecoder(opts ...DecoderOption) *Decoder {
 
 // Decode parses the provided TOON document.
 func (d *Decoder) Decode(data []byte) (any, error) {
+	return d.decode(data, false)
+}
+
+// decode parses data. In raw mode, used by Unmarshal, objects are returned as
+// Object values that preserve document order rather than as map[string]any,
+// and numbers as numberLiteral values that retain their source text.
+// WithUseNumber and WithOrderedObjects decode the same way and then export
+// the values they do not keep.
+func (d *Decoder) decode(data []byte, raw bool) (any, error) {
+	if d.cfg.maxInputBytes > 0 && len(data) > d.cfg.maxInputBytes {
+		return nil, errInputTooLarge(d.cfg.maxInputBytes)
+	}
 	parser, err := newParser(string(data), d.cfg)
 	if err != nil {
 		return nil, err
 	}
+	useNumber := d.cfg.useNumber && !raw
+	ordered := d.cfg.orderedObjects && !raw
+	parser.ordered = raw || useNumber || ordered
+	parser.literals = raw || useNumber
 	value, err := parser.parseDocument()
 	if err != nil {
 		return nil, err
 	}
+	switch {
+	case useNumber && ordered:
+		return exportValue(value, exportOrderedJSON), nil
+	case useNumber:
+		return exportValue(value, exportJSON), nil
+	}
 	return value, nil
 }
 
@@ -55,63 +79,175 @@ func DecodeString(s string, opts ...DecoderOption) (any, error) {
 	return NewDecoder(opts...).DecodeString(s)
 }
 
+// DecodeFragment decodes a document whose lines all share a common leading
+// indentation, such as a subtree cut out of a larger document. The smallest
+// indentation among non-blank lines is removed before decoding; line numbers
+// in errors still refer to data.
+func (d *Decoder) DecodeFragment(data []byte) (any, error) {
+	return d.Decode(dedent(data))
+}
+
+// DecodeFragment decodes the fragment in data using a temporary decoder.
+func DecodeFragment(data []byte, opts ...DecoderOption) (any, error) {
+	return NewDecoder(opts...).DecodeFragment(data)
+}
+
+// dedent removes the leading spaces common to every non-blank line of data.
+func dedent(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	common := -1
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		if spaces := len(line) - len(trimmed); common == -1 || spaces < common {
+			common = spaces
+		}
+	}
+	if common <= 0 {
+		return data
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = line[common:]
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
 type parser struct {
-	lines []parsedLine
-	pos   int
-	cfg   decoderOptions
+	lines    []parsedLine
+	pos      int
+	cfg      decoderOptions
+	elements int
+	ordered  bool
+	literals bool
+	spans    map[string]Span
+	path     string
+	// fill is the normalized placeholder of WithDecoderTabularFillValue.
+	fill normalizedValue
+	// cells is reused by splitValues, whose results are consumed before the
+	// next row is split.
+	cells []string
 }
 
 type parsedLine struct {
 	number  int
 	indent  int
 	content string
-	raw     string
 	blank   bool
 }
 
+// newParser indexes the lines of input. Line contents are substrings of
+// input, so the document text is never copied line by line.
 func newParser(input string, cfg decoderOptions) (*parser, error) {
-	rawLines := splitLines(input)
-	lines := make([]parsedLine, 0, len(rawLines))
-	for idx, raw := range rawLines {
+	if number, spaces, ok := firstIndentStep(input); ok && !cfg.tabIndent && spaces != cfg.indentSize {
+		switch {
+		case cfg.autoIndent:
+			cfg.indentSize = spaces
+		case cfg.strict && spaces%cfg.indentSize == 0:
+			err := errorAtf(number, "first indented line uses %d spaces but the indentation step is %d; use WithDecoderIndent(%d) or WithAutoIndent", spaces, cfg.indentSize, spaces)
+			if err := cfg.report(err); err != nil {
+				return nil, err
+			}
+			cfg.indentSize = spaces
+		}
+	}
+	lines := make([]parsedLine, 0, strings.Count(input, "\n")+1)
+	preamble := cfg.skipSchemaHeader
+	number := 0
+	for raw, rest, ok := nextLine(input); ok; raw, rest, ok = nextLine(rest) {
+		number++
+		if cfg.lint != nil && cfg.lint.skip[number] {
+			continue
+		}
+		if cfg.maxLineBytes > 0 && len(raw) > cfg.maxLineBytes {
+			return nil, errorAtf(number, "line exceeds %d bytes", cfg.maxLineBytes)
+		}
+		if preamble {
+			trimmed := strings.TrimSpace(raw)
+			if strings.HasPrefix(trimmed, "#") {
+				lines = append(lines, parsedLine{number: number, blank: true})
+				continue
+			}
+			preamble = trimmed == ""
+		}
 		if raw == "" {
-			lines = append(lines, parsedLine{
-				number:  idx + 1,
-				indent:  0,
-				content: "",
-				raw:     "",
-				blank:   true,
-			})
+			lines = append(lines, parsedLine{number: number, blank: true})
 			continue
 		}
 		indent, content, err := computeIndent(raw, cfg)
 		if err != nil {
-			return nil, errorWrap(idx+1, err)
+			if err := cfg.report(errorWrap(number, err)); err != nil {
+				return nil, err
+			}
+			lenient := cfg
+			lenient.strict = false
+			indent, content, _ = computeIndent(raw, lenient)
 		}
 		lines = append(lines, parsedLine{
-			number:  idx + 1,
+			number:  number,
 			indent:  indent,
 			content: content,
-			raw:     raw,
 			blank:   strings.TrimSpace(content) == "",
 		})
 	}
+	var fill normalizedValue
+	if cfg.tabularFillValue != nil {
+		var err error
+		if fill, err = normalizeFillValue(cfg.tabularFillValue); err != nil {
+			return nil, err
+		}
+	}
 	return &parser{
 		lines: lines,
 		cfg:   cfg,
+		fill:  fill,
 	}, nil
 }
 
-func splitLines(input string) []string {
-	input = strings.ReplaceAll(input, "\r\n", "\n")
-	lines := strings.Split(input, "\n")
-	// Drop trailing empty line caused by final newline.
-	if len(lines) > 0 && lines[len(lines)-1] == "" {
-		lines = lines[:len(lines)-1]
+// nextLine splits the first line off input, dropping its "\n" or "\r\n"
+// terminator. ok is false once input is exhausted, so a final newline does not
+// produce a trailing empty line.
+func nextLine(input string) (line, rest string, ok bool) {
+	if input == "" {
+		return "", "", false
+	}
+	line, rest, found := strings.Cut(input, "\n")
+	if found {
+		line = strings.TrimSuffix(line, "\r")
+	}
+	return line, rest, true
+}
+
+// firstIndentStep returns the number of leading spaces on the first indented
+// line that follows other content. In a well-formed document that line is one
+// level deep, so its indentation is the step the document uses.
+func firstIndentStep(input string) (int, int, bool) {
+	seenContent := false
+	number := 0
+	for raw, rest, ok := nextLine(input); ok; raw, rest, ok = nextLine(rest) {
+		number++
+		trimmed := strings.TrimLeft(raw, " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		spaces := len(raw) - len(trimmed)
+		if spaces > 0 && seenContent {
+			return number, spaces, true
+		}
+		seenContent = true
 	}
-	return lines
+	return 0, 0, false
 }
 
 func computeIndent(line string, cfg decoderOptions) (int, string, error) {
+	if cfg.tabIndent {
+		return computeTabIndent(line, cfg)
+	}
 	indent := 0
 	for i := 0; i < len(line); i++ {
 		switch line[i] {
@@ -134,10 +270,34 @@ func computeIndent(line string, cfg decoderOptions) (int, string, error) {
 	return 0, "", nil
 }
 
+// computeTabIndent counts each leading tab as one indentation level. Spaces
+// are an error in strict mode and are otherwise counted against the
+// indentation step.
+func computeTabIndent(line string, cfg decoderOptions) (int, string, error) {
+	tabs, spaces := 0, 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\t':
+			tabs++
+		case ' ':
+			spaces++
+		default:
+			if cfg.strict && spaces > 0 {
+				return 0, "", errors.New("mixed tabs and spaces in indentation (tab indentation)")
+			}
+			return tabs + spaces/cfg.indentSize, line[i:], nil
+		}
+	}
+	return 0, "", nil
+}
+
 func (p *parser) parseDocument() (any, error) {
 	p.skipBlankLinesOutsideArrays()
 	if p.pos >= len(p.lines) {
-		return map[string]any{}, nil
+		if p.cfg.errorOnEmpty {
+			return nil, ErrEmptyDocument
+		}
+		return p.newObject().value(), nil
 	}
 
 	nonBlank := p.countRemainingNonBlank()
@@ -150,7 +310,7 @@ func (p *parser) parseDocument() (any, error) {
 
 	if nonBlank == 1 && !ok && !isKeyValue(first.content) {
 		token := strings.TrimSpace(first.content)
-		value, err := decodePrimitiveToken(token)
+		value, err := p.decodeValue(token)
 		if err != nil {
 			return nil, errorWrap(first.number, err)
 		}
@@ -158,16 +318,23 @@ func (p *parser) parseDocument() (any, error) {
 		return value, nil
 	}
 
-	if ok && first.indent == 0 && header.key == "" {
+	if ok && first.indent == 0 && header.keyless() {
 		p.pos++
-		return p.parseArray(header, 0)
+		value, err := p.parseArray(header, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectEnd(); err != nil {
+			return nil, err
+		}
+		return value, nil
 	}
 
 	return p.parseObject(0)
 }
 
-func (p *parser) parseObject(depth int) (map[string]any, error) {
-	result := make(map[string]any)
+func (p *parser) parseObject(depth int) (any, error) {
+	result := p.newObject()
 	for p.pos < len(p.lines) {
 		line := p.current()
 		if line.blank {
@@ -185,15 +352,19 @@ func (p *parser) parseObject(depth int) (map[string]any, error) {
 			return nil, errorWrap(line.number, err)
 		}
 		if isHeader {
-			if header.key == "" {
+			if header.keyless() {
 				return nil, errorAt(line.number, "arrays within objects must have a key")
 			}
 			p.pos++
+			mark := p.enterKey(header.key, line.number)
 			value, err := p.parseArray(header, depth)
 			if err != nil {
 				return nil, err
 			}
-			result[header.key] = value
+			p.leave(mark, value)
+			if err := p.setField(result, header.key, header.quotedKey, value); err != nil {
+				return nil, errorWrap(line.number, err)
+			}
 			continue
 		}
 
@@ -203,199 +374,356 @@ func (p *parser) parseObject(depth int) (map[string]any, error) {
 		}
 		p.pos++
 		if rest == "" {
+			mark := p.enterKey(key, line.number)
 			nextValue, err := p.parseObject(depth + 1)
 			if err != nil {
 				return nil, err
 			}
-			result[key] = nextValue
+			p.leave(mark, nextValue)
+			if err := p.setField(result, key, isQuotedKey(line.content), nextValue); err != nil {
+				return nil, errorWrap(line.number, err)
+			}
 			continue
 		}
 
-		value, err := decodePrimitiveToken(rest)
+		value, err := p.decodeValue(rest)
 		if err != nil {
 			return nil, errorWrap(line.number, err)
 		}
-		result[key] = value
+		if err := p.setField(result, key, isQuotedKey(line.content), value); err != nil {
+			return nil, errorWrap(line.number, err)
+		}
 	}
-	return result, nil
+	return result.value(), nil
 }
 
 func (p *parser) parseArray(header parsedHeader, depth int) (any, error) {
-	delimiter := header.delimiter.rune()
-	var values []any
-	ctx := p.cfg
-
 	if len(header.inlineValues) > 0 {
-		raw, err := parsepkg.SplitInlineValues(header.inlineValues, delimiter)
+		return p.parseInlineValues(header)
+	}
+	values := make([]any, 0, p.initialCapacity(header.length))
+	for {
+		mark := p.enterIndex(len(values))
+		value, ok, err := p.nextElement(header, depth, len(values))
 		if err != nil {
-			return nil, errorWrap(p.lines[p.pos-1].number, err)
+			return nil, err
 		}
-		for _, token := range raw {
-			value, err := decodePrimitiveToken(token)
-			if err != nil {
-				return nil, errorWrap(p.lines[p.pos-1].number, err)
-			}
-			values = append(values, value)
+		p.leave(mark, value)
+		if !ok {
+			break
+		}
+		values = append(values, value)
+	}
+	if err := p.checkLength(header, len(values)); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// parseInlineValues decodes the values that follow an array header on the
+// same line.
+func (p *parser) parseInlineValues(header parsedHeader) ([]any, error) {
+	lineNumber := p.lines[p.pos-1].number
+	raw, err := p.splitValues(header.inlineValues, header.delimiter, header.length)
+	if err != nil {
+		return nil, errorWrap(lineNumber, err)
+	}
+	var values []any
+	for _, token := range raw {
+		if err := p.countElement(lineNumber); err != nil {
+			return nil, err
 		}
-		if ctx.strict && len(values) != header.length {
-			return nil, errorAtf(p.lines[p.pos-1].number, "inline array length mismatch; expected %d, got %d", header.length, len(values))
+		value, err := p.decodePrimitive(token)
+		if err != nil {
+			return nil, errorWrap(lineNumber, err)
+		}
+		values = append(values, value)
+	}
+	if p.cfg.strict && len(values) != header.length {
+		err := errorAtf(lineNumber, "inline array length mismatch; expected %d, got %d", header.length, len(values))
+		if err := p.cfg.report(err); err != nil {
+			return nil, err
 		}
-		return values, nil
 	}
+	return values, nil
+}
 
+// splitValues splits an inline array or tabular row by delimiter. With
+// WithDelimiterFallback in permissive mode, a split that does not yield the
+// want values declared by the header is retried with commas, and the comma
+// split is used when it matches.
+func (p *parser) splitValues(s string, delimiter Delimiter, want int) ([]string, error) {
+	raw, err := parsepkg.AppendInlineValues(p.cells[:0], s, delimiter.rune(), p.cfg.escapedDelimiters)
+	p.cells = raw
+	if err != nil || len(raw) == want || !p.cfg.delimiterFallback || p.cfg.strict || delimiter == DelimiterComma {
+		return raw, err
+	}
+	if fallback, err := parsepkg.SplitInlineValues(s, DelimiterComma.rune(), p.cfg.escapedDelimiters); err == nil && len(fallback) == want {
+		return fallback, nil
+	}
+	return raw, nil
+}
+
+// nextElement decodes the next tabular row or list item of a multi-line
+// array, reporting false once the array's scope ends. count is the number of
+// elements decoded so far.
+func (p *parser) nextElement(header parsedHeader, depth int, count int) (any, bool, error) {
 	if len(header.fields) > 0 {
-		rows := make([]any, 0, header.length)
-		for p.pos < len(p.lines) {
-			line := p.current()
-			if line.blank {
-				if ctx.strict {
-					if nextIndent, ok := p.nextNonBlankIndent(p.pos); !ok || nextIndent <= depth {
-						break
-					}
-					return nil, errorAt(line.number, "blank line inside tabular array")
+		if count == 0 {
+			for _, field := range header.fields {
+				if err := p.checkKey(field); err != nil {
+					return nil, false, errorWrap(p.lines[p.pos-1].number, err)
 				}
-				p.pos++
-				continue
-			}
-			if line.indent <= depth {
-				break
 			}
-			if line.indent != depth+1 {
-				return nil, errorAt(line.number, "invalid indentation for tabular row")
+		}
+		row, ok, err := p.nextTabularRow(header, depth)
+		if ok && p.cfg.strict && count+1 > header.length {
+			err := errorAtf(p.lines[p.pos-1].number, "too many tabular rows (expected %d)", header.length)
+			if err := p.cfg.report(err); err != nil {
+				return nil, false, err
 			}
-			trimmed := strings.TrimSpace(line.content)
-			if indexOutsideQuotes(trimmed, ':') != -1 {
-				break
+		}
+		return row, ok, err
+	}
+	return p.nextListItem(depth)
+}
+
+// checkLength enforces the declared length of a multi-line array in strict
+// mode.
+func (p *parser) checkLength(header parsedHeader, count int) error {
+	if !p.cfg.strict || count == header.length {
+		return nil
+	}
+	lineNumber := p.lines[p.pos-1].number
+	if len(header.fields) > 0 {
+		return p.cfg.report(errorAtf(lineNumber, "tabular length mismatch; expected %d rows", header.length))
+	}
+	return p.cfg.report(errorAtf(lineNumber, "list length mismatch; expected %d items", header.length))
+}
+
+func (p *parser) nextTabularRow(header parsedHeader, depth int) (any, bool, error) {
+	for p.pos < len(p.lines) {
+		line := p.current()
+		if line.blank {
+			if p.cfg.strict {
+				if nextIndent, ok := p.nextNonBlankIndent(p.pos); !ok || nextIndent <= depth {
+					return nil, false, nil
+				}
+				if err := p.cfg.report(errorAt(line.number, "blank line inside tabular array")); err != nil {
+					return nil, false, err
+				}
 			}
 			p.pos++
-			raw, err := parsepkg.SplitInlineValues(trimmed, delimiter)
-			if err != nil {
-				return nil, errorWrap(line.number, err)
-			}
-			if ctx.strict && len(raw) != len(header.fields) {
-				return nil, errorAt(line.number, "tabular row width mismatch")
+			continue
+		}
+		if line.indent <= depth {
+			return nil, false, nil
+		}
+		if line.indent != depth+1 {
+			return nil, false, errorAt(line.number, "invalid indentation for tabular row")
+		}
+		trimmed := strings.TrimSpace(line.content)
+		if indexOutsideQuotes(trimmed, ':') != -1 {
+			return nil, false, nil
+		}
+		if err := p.countElement(line.number); err != nil {
+			return nil, false, err
+		}
+		p.pos++
+		raw, err := p.splitValues(trimmed, header.delimiter, len(header.fields))
+		if err != nil {
+			return nil, false, errorWrap(line.number, err)
+		}
+		if p.cfg.strict && len(raw) != len(header.fields) {
+			if err := p.cfg.report(errorAt(line.number, "tabular row width mismatch")); err != nil {
+				return nil, false, err
 			}
-			row := make(map[string]any, len(header.fields))
-			for idx, field := range header.fields {
-				if idx >= len(raw) {
-					break
-				}
-				value, err := decodePrimitiveToken(raw[idx])
-				if err != nil {
-					return nil, errorWrap(line.number, err)
-				}
-				row[field] = value
+		}
+		row := p.newRow(len(header.fields))
+		filled := p.fill != nil && len(raw) == len(header.fields)
+		for idx, field := range header.fields {
+			if idx >= len(raw) {
+				break
 			}
-			rows = append(rows, row)
-			if ctx.strict && len(rows) > header.length {
-				return nil, errorAtf(line.number, "too many tabular rows (expected %d)", header.length)
+			value, err := p.decodePrimitive(raw[idx])
+			if err != nil {
+				return nil, false, errorWrap(line.number, err)
 			}
+			filled = filled && p.isFillCell(raw[idx], value)
+			row.set(field, value)
 		}
-		if ctx.strict && len(rows) != header.length {
-			return nil, errorAtf(p.lines[p.pos-1].number, "tabular length mismatch; expected %d rows", header.length)
+		if filled {
+			return nil, true, nil
 		}
-		return rows, nil
+		return row.value(), true, nil
+	}
+	return nil, false, nil
+}
+
+// isFillCell reports whether the cell decoded as value from token holds the
+// WithDecoderTabularFillValue placeholder.
+func (p *parser) isFillCell(token string, value any) bool {
+	switch fill := p.fill.(type) {
+	case numberValue:
+		return strings.TrimSpace(token) == fill.literal
+	case string:
+		s, ok := value.(string)
+		return ok && s == fill
+	case bool:
+		b, ok := value.(bool)
+		return ok && b == fill
 	}
+	return false
+}
 
-	values = make([]any, 0, header.length)
+func (p *parser) nextListItem(depth int) (any, bool, error) {
 	for p.pos < len(p.lines) {
 		line := p.current()
 		if line.blank {
-			if ctx.strict {
+			if p.cfg.strict {
 				if nextIndent, ok := p.nextNonBlankIndent(p.pos); !ok || nextIndent <= depth {
-					break
+					return nil, false, nil
+				}
+				if err := p.cfg.report(errorAt(line.number, "blank line inside list array")); err != nil {
+					return nil, false, err
 				}
-				return nil, errorAt(line.number, "blank line inside list array")
 			}
 			p.pos++
 			continue
 		}
 		if line.indent <= depth {
-			break
+			return nil, false, nil
 		}
 		if line.indent != depth+1 {
-			return nil, errorAt(line.number, "invalid indentation for list item")
+			return nil, false, errorAt(line.number, "invalid indentation for list item")
 		}
 		if !strings.HasPrefix(line.content, "-") {
-			break
+			return nil, false, nil
+		}
+		if err := p.countElement(line.number); err != nil {
+			return nil, false, err
 		}
 		itemContent := strings.TrimSpace(line.content[1:])
 		p.pos++
-		if itemContent == "" {
-			values = append(values, map[string]any{})
-			continue
+		value, err := p.parseListItem(line, itemContent, depth)
+		if err != nil {
+			return nil, false, err
 		}
+		return value, true, nil
+	}
+	return nil, false, nil
+}
 
-		if strings.HasPrefix(itemContent, "[") {
-			itemHeader, ok, err := tryParseHeader(itemContent)
-			if err != nil {
-				return nil, errorWrap(line.number, err)
-			}
-			if !ok {
-				return nil, errorAt(line.number, "invalid array header in list item")
-			}
-			itemValue, err := p.parseArray(itemHeader, depth+1)
-			if err != nil {
-				return nil, err
-			}
-			values = append(values, itemValue)
-			continue
+// parseListItem decodes the content following a "- " marker on line, together
+// with any nested lines that belong to the item.
+func (p *parser) parseListItem(line parsedLine, itemContent string, depth int) (any, error) {
+	if itemContent == "" || itemContent == emptyObjectLiteral || itemContent == emptyArrayLiteral {
+		return p.decodeValue(itemContent)
+	}
+
+	if strings.HasPrefix(itemContent, "[") {
+		itemHeader, ok, err := tryParseHeader(itemContent)
+		if err != nil {
+			return nil, errorWrap(line.number, err)
 		}
+		if !ok {
+			return nil, errorAt(line.number, "invalid array header in list item")
+		}
+		return p.parseArray(itemHeader, depth+1)
+	}
 
-		if header, isHeader, err := tryParseHeader(itemContent); err != nil {
+	if header, isHeader, err := tryParseHeader(itemContent); err != nil {
+		return nil, errorWrap(line.number, err)
+	} else if isHeader {
+		if header.keyless() {
+			return nil, errorAt(line.number, "arrays within objects must have a key")
+		}
+		mark := p.enterKey(header.key, line.number)
+		arrayValue, err := p.parseArray(header, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		p.leave(mark, arrayValue)
+		obj := p.newObject()
+		if err := p.setField(obj, header.key, header.quotedKey, arrayValue); err != nil {
 			return nil, errorWrap(line.number, err)
-		} else if isHeader {
-			if header.key == "" {
-				return nil, errorAt(line.number, "arrays within objects must have a key")
-			}
-			arrayValue, err := p.parseArray(header, depth+1)
-			if err != nil {
-				return nil, err
-			}
-			obj := map[string]any{header.key: arrayValue}
-			if err := p.collectObjectListSiblings(obj, depth); err != nil {
-				return nil, err
-			}
-			values = append(values, obj)
-			continue
 		}
+		if err := p.collectObjectListSiblings(obj, depth); err != nil {
+			return nil, err
+		}
+		return obj.value(), nil
+	}
 
-		if isKeyValue(itemContent) {
-			key, rest, err := splitKeyValue(itemContent)
+	if isKeyValue(itemContent) {
+		key, rest, err := splitKeyValue(itemContent)
+		if err != nil {
+			return nil, errorWrap(line.number, err)
+		}
+		if rest == "" {
+			mark := p.enterKey(key, line.number)
+			obj, err := p.parseObject(depth + 3)
 			if err != nil {
-				return nil, errorWrap(line.number, err)
-			}
-			if rest == "" {
-				obj, err := p.parseObject(depth + 3)
-				if err != nil {
-					return nil, err
-				}
-				values = append(values, map[string]any{key: obj})
-				continue
+				return nil, err
 			}
-			val, err := decodePrimitiveToken(rest)
-			if err != nil {
+			p.leave(mark, obj)
+			item := p.newObject()
+			if err := p.setField(item, key, isQuotedKey(itemContent), obj); err != nil {
 				return nil, errorWrap(line.number, err)
 			}
-			obj := map[string]any{key: val}
-			if err := p.collectObjectListSiblings(obj, depth); err != nil {
+			if err := p.collectObjectListSiblings(item, depth); err != nil {
 				return nil, err
 			}
-			values = append(values, obj)
-			continue
+			return item.value(), nil
 		}
-
-		value, err := decodePrimitiveToken(itemContent)
+		val, err := p.decodeValue(rest)
 		if err != nil {
 			return nil, errorWrap(line.number, err)
 		}
-		values = append(values, value)
+		obj := p.newObject()
+		if err := p.setField(obj, key, isQuotedKey(itemContent), val); err != nil {
+			return nil, errorWrap(line.number, err)
+		}
+		if err := p.collectObjectListSiblings(obj, depth); err != nil {
+			return nil, err
+		}
+		return obj.value(), nil
 	}
 
-	if ctx.strict && len(values) != header.length {
-		return nil, errorAtf(p.lines[p.pos-1].number, "list length mismatch; expected %d items", header.length)
+	value, err := p.decodeValue(itemContent)
+	if err != nil {
+		return nil, errorWrap(line.number, err)
 	}
-	return values, nil
+	return value, nil
+}
+
+// checkKey rejects keys listed with WithForbiddenKeys.
+func (p *parser) checkKey(key string) error {
+	if _, forbidden := p.cfg.forbiddenKeys[key]; forbidden {
+		return fmt.Errorf("forbidden key %q", key)
+	}
+	return nil
+}
+
+// countElement records one decoded array element and enforces the
+// WithMaxElements limit.
+func (p *parser) countElement(line int) error {
+	p.elements++
+	if p.cfg.maxElements > 0 && p.elements > p.cfg.maxElements {
+		return errorAtf(line, "array element limit of %d exceeded", p.cfg.maxElements)
+	}
+	return nil
+}
+
+// initialCapacity bounds the slice pre-allocation for an array declaring
+// length elements. Every row or list item occupies at least one line, so the
+// remaining line count caps the allocation regardless of the declared length,
+// as does the remaining element budget.
+func (p *parser) initialCapacity(length int) int {
+	length = min(length, len(p.lines)-p.pos)
+	if p.cfg.maxElements > 0 {
+		length = min(length, p.cfg.maxElements-p.elements)
+	}
+	return max(length, 0)
 }
 
 func (p *parser) current() parsedLine {
@@ -411,6 +739,16 @@ func (p *parser) skipBlankLinesOutsideArrays() {
 	}
 }
 
+// expectEnd reports an error if any content remains after a root array, which
+// would otherwise be dropped silently.
+func (p *parser) expectEnd() error {
+	p.skipBlankLinesOutsideArrays()
+	if p.pos < len(p.lines) {
+		return errorAt(p.current().number, "unexpected content after root array")
+	}
+	return nil
+}
+
 func (p *parser) countRemainingNonBlank() int {
 	count := 0
 	for _, line := range p.lines[p.pos:] {
@@ -430,7 +768,7 @@ func (p *parser) nextNonBlankIndent(from int) (int, bool) {
 	return 0, false
 }
 
-func (p *parser) collectObjectListSiblings(obj map[string]any, depth int) error {
+func (p *parser) collectObjectListSiblings(obj *objectBuilder, depth int) error {
 	for p.pos < len(p.lines) {
 		next := p.current()
 		if next.blank {
@@ -438,7 +776,9 @@ func (p *parser) collectObjectListSiblings(obj map[string]any, depth int) error
 				if nextIndent, ok := p.nextNonBlankIndent(p.pos); !ok || nextIndent <= depth+1 {
 					break
 				}
-				return errorAt(next.number, "blank line inside object list item")
+				if err := p.cfg.report(errorAt(next.number, "blank line inside object list item")); err != nil {
+					return err
+				}
 			}
 			p.pos++
 			continue
@@ -453,14 +793,18 @@ func (p *parser) collectObjectListSiblings(obj map[string]any, depth int) error
 			return errorWrap(next.number, err)
 		} else if isHeader {
 			p.pos++
+			mark := p.enterKey(header.key, next.number)
 			value, err := p.parseArray(header, depth+1)
 			if err != nil {
 				return err
 			}
-			if header.key == "" {
+			p.leave(mark, value)
+			if header.keyless() {
 				return errorAt(next.number, "arrays within objects must have a key")
 			}
-			obj[header.key] = value
+			if err := p.setField(obj, header.key, header.quotedKey, value); err != nil {
+				return errorWrap(next.number, err)
+			}
 			continue
 		}
 		key, rest, err := splitKeyValue(next.content)
@@ -469,30 +813,131 @@ func (p *parser) collectObjectListSiblings(obj map[string]any, depth int) error
 		}
 		p.pos++
 		if rest == "" {
+			mark := p.enterKey(key, next.number)
 			nested, err := p.parseObject(depth + 3)
 			if err != nil {
 				return err
 			}
-			obj[key] = nested
+			p.leave(mark, nested)
+			if err := p.setField(obj, key, isQuotedKey(next.content), nested); err != nil {
+				return errorWrap(next.number, err)
+			}
 		} else {
-			value, err := decodePrimitiveToken(rest)
+			value, err := p.decodeValue(rest)
 			if err != nil {
 				return errorWrap(next.number, err)
 			}
-			obj[key] = value
+			if err := p.setField(obj, key, isQuotedKey(next.content), value); err != nil {
+				return errorWrap(next.number, err)
+			}
 		}
 	}
 	return nil
 }
 
+// objectBuilder accumulates the fields of a decoded object, producing either a
+// map[string]any or, in ordered mode, an Object in document order. Repeated
+// keys keep their first position and take the last value in both modes.
+type objectBuilder struct {
+	fields map[string]any
+	order  []Field
+	index  map[string]int
+	nested bool
+}
+
+func (p *parser) newObject() *objectBuilder {
+	if p.ordered {
+		return &objectBuilder{index: make(map[string]int)}
+	}
+	return &objectBuilder{fields: make(map[string]any)}
+}
+
+// newRow returns a builder sized for a tabular row of size cells. Rows are
+// flat and short, so an ordered row finds repeated keys by scanning its fields
+// rather than keeping a key index.
+func (p *parser) newRow(size int) objectBuilder {
+	if p.ordered {
+		return objectBuilder{order: make([]Field, 0, size)}
+	}
+	return objectBuilder{fields: make(map[string]any, size)}
+}
+
+func (b *objectBuilder) set(key string, value any) {
+	if b.fields != nil {
+		b.fields[key] = value
+		return
+	}
+	if idx, ok := b.position(key); ok {
+		b.order[idx].Value = value
+		return
+	}
+	if b.index != nil {
+		b.index[key] = len(b.order)
+	}
+	b.order = append(b.order, Field{Key: key, Value: value})
+}
+
+func (b *objectBuilder) get(key string) (any, bool) {
+	if b.fields != nil {
+		value, ok := b.fields[key]
+		return value, ok
+	}
+	if idx, ok := b.position(key); ok {
+		return b.order[idx].Value, true
+	}
+	return nil, false
+}
+
+// position returns the index of key in an ordered builder.
+func (b *objectBuilder) position(key string) (int, bool) {
+	if b.index != nil {
+		idx, ok := b.index[key]
+		return idx, ok
+	}
+	for idx, field := range b.order {
+		if field.Key == key {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+func (b *objectBuilder) value() any {
+	if b.fields != nil {
+		if b.nested {
+			for key, value := range b.fields {
+				if child, ok := value.(*objectBuilder); ok {
+					b.fields[key] = child.value()
+				}
+			}
+		}
+		return b.fields
+	}
+	if b.nested {
+		for i, field := range b.order {
+			if child, ok := field.Value.(*objectBuilder); ok {
+				b.order[i].Value = child.value()
+			}
+		}
+	}
+	return Object{Fields: b.order}
+}
+
 type parsedHeader struct {
 	key          string
+	quotedKey    bool
 	length       int
 	delimiter    Delimiter
 	fields       []string
 	inlineValues string
 }
 
+// keyless reports whether the header has no key at all, as opposed to the
+// explicitly quoted empty key "".
+func (h parsedHeader) keyless() bool {
+	return h.key == "" && !h.quotedKey
+}
+
 func tryParseHeader(content string) (parsedHeader, bool, error) {
 	colon := indexOutsideQuotes(content, ':')
 	if colon == -1 {
@@ -527,6 +972,7 @@ func tryParseHeader(content string) (parsedHeader, bool, error) {
 			return parsedHeader{}, false, err
 		}
 		header.key = key
+		header.quotedKey = keyPart[0] == '"'
 	}
 
 	length, delim, err := parseBracketSegment(bracketSegment)
@@ -542,7 +988,7 @@ func tryParseHeader(content string) (parsedHeader, bool, error) {
 		}
 		inner := fieldSegment[1 : len(fieldSegment)-1]
 		if inner != "" {
-			rawFields, err := parsepkg.SplitInlineValues(inner, delim.rune())
+			rawFields, err := parsepkg.SplitInlineValues(inner, delim.rune(), false)
 			if err != nil {
 				return parsedHeader{}, false, err
 			}
@@ -626,6 +1072,65 @@ func decodeKeyToken(token string) (string, error) {
 	return token, nil
 }
 
+// decodeValue decodes a token standing alone in value position: the root, a
+// key's value, or a list item. Besides primitives it accepts the empty
+// literals "{}" and "[]", and treats empty content as an empty object. Cells
+// of inline and tabular arrays go through decodePrimitive instead.
+func (p *parser) decodeValue(token string) (any, error) {
+	switch token {
+	case "", emptyObjectLiteral:
+		return p.newObject().value(), nil
+	case emptyArrayLiteral:
+		return []any{}, nil
+	}
+	return p.decodePrimitive(token)
+}
+
+// decodePrimitive decodes a primitive token, honouring the configured literal
+// extensions before falling back to the core rules.
+func (p *parser) decodePrimitive(token string) (any, error) {
+	if p.cfg.nullLiteral != "" && token == p.cfg.nullLiteral {
+		return nil, nil
+	}
+	if b, ok := p.cfg.boolLiterals[token]; ok && !formatpkg.LooksNumeric(token) {
+		return b, nil
+	}
+	if p.cfg.caseInsensitive && !p.cfg.strict {
+		switch {
+		case strings.EqualFold(token, "true"):
+			return true, nil
+		case strings.EqualFold(token, "false"):
+			return false, nil
+		case strings.EqualFold(token, "null"):
+			return nil, nil
+		}
+	}
+	if p.cfg.allowNonFinite {
+		switch token {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+	}
+	if p.cfg.extendedNumbers {
+		if n, ok := parseExtendedInteger(token); ok {
+			num, _ := new(big.Float).SetInt(n).Float64()
+			if p.literals {
+				return numberLiteral{value: num, text: n.String()}, nil
+			}
+			return num, nil
+		}
+	}
+	value, err := decodePrimitiveToken(token)
+	if num, ok := value.(float64); ok && p.literals {
+		return numberLiteral{value: num, text: token}, err
+	}
+	return value, err
+}
+
 func decodePrimitiveToken(token string) (any, error) {
 	if token == "" {
 		return "", nil
@@ -657,6 +1162,39 @@ func decodePrimitiveToken(token string) (any, error) {
 	return token, nil
 }
 
+// parseExtendedInteger parses an optionally negative integer written with a
+// 0x, 0o or 0b prefix. Underscores and prefix-less octal are not accepted, so
+// tokens such as "0123" remain subject to the leading-zero rule.
+func parseExtendedInteger(token string) (*big.Int, bool) {
+	digits := strings.TrimPrefix(token, "-")
+	if len(digits) < 3 || digits[0] != '0' {
+		return nil, false
+	}
+	var base int
+	switch digits[1] {
+	case 'x', 'X':
+		base = 16
+	case 'o', 'O':
+		base = 8
+	case 'b', 'B':
+		base = 2
+	default:
+		return nil, false
+	}
+	digits = digits[2:]
+	if strings.ContainsRune(digits, '_') || digits[0] == '+' || digits[0] == '-' {
+		return nil, false
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, false
+	}
+	if token[0] == '-' {
+		n.Neg(n)
+	}
+	return n, true
+}
+
 func hasForbiddenLeadingZeros(token string) bool {
 	if len(token) < 2 {
 		return false
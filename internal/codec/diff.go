@@ -0,0 +1,153 @@
+package codec
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// ChangeKind classifies a Change reported by Diff.
+type ChangeKind int
+
+const (
+	// ChangeAdded marks a path present only in the second document.
+	ChangeAdded ChangeKind = iota + 1
+	// ChangeRemoved marks a path present only in the first document.
+	ChangeRemoved
+	// ChangeModified marks a path whose value differs between the documents.
+	ChangeModified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return "change(unknown)"
+	}
+}
+
+// Change describes one difference found by Diff. Path uses the notation of
+// decode errors: dotted keys with [index] suffixes, and "" for the root. Old
+// and New hold the values as Decode would return them; Old is nil for
+// additions and New is nil for removals, so Kind distinguishes those from an
+// explicit null.
+type Change struct {
+	Kind ChangeKind
+	Path string
+	Old  any
+	New  any
+}
+
+// Diff decodes the TOON documents a and b and reports how b differs from a.
+// Objects are compared key by key and arrays index by index; when the values
+// at a path have different types the whole value is reported as modified.
+// Changes follow the key order of a, with additions listed after the keys
+// both objects share. Numbers compare by value, so 1.0 and 1 are equal.
+func Diff(a, b []byte, opts ...DecoderOption) ([]Change, error) {
+	dec := NewDecoder(opts...)
+	left, err := dec.decode(a, true)
+	if err != nil {
+		return nil, err
+	}
+	right, err := dec.decode(b, true)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	diffValues(&changes, "", left, right)
+	return changes, nil
+}
+
+func diffValues(changes *[]Change, path string, a, b any) {
+	switch left := a.(type) {
+	case Object:
+		if right, ok := b.(Object); ok {
+			diffObjects(changes, path, left, right)
+			return
+		}
+	case []any:
+		if right, ok := b.([]any); ok {
+			diffArrays(changes, path, left, right)
+			return
+		}
+	default:
+		if equalScalars(a, b) {
+			return
+		}
+	}
+	*changes = append(*changes, Change{Kind: ChangeModified, Path: path, Old: plainValue(a), New: plainValue(b)})
+}
+
+func diffObjects(changes *[]Change, path string, a, b Object) {
+	index := make(map[string]int, len(b.Fields))
+	for i, field := range b.Fields {
+		index[field.Key] = i
+	}
+	seen := make(map[string]bool, len(a.Fields))
+	for _, field := range a.Fields {
+		seen[field.Key] = true
+		fieldPath := diffKeyPath(path, field.Key)
+		i, ok := index[field.Key]
+		if !ok {
+			*changes = append(*changes, Change{Kind: ChangeRemoved, Path: fieldPath, Old: plainValue(field.Value)})
+			continue
+		}
+		diffValues(changes, fieldPath, field.Value, b.Fields[i].Value)
+	}
+	for _, field := range b.Fields {
+		if seen[field.Key] {
+			continue
+		}
+		*changes = append(*changes, Change{Kind: ChangeAdded, Path: diffKeyPath(path, field.Key), New: plainValue(field.Value)})
+	}
+}
+
+func diffArrays(changes *[]Change, path string, a, b []any) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= len(b):
+			*changes = append(*changes, Change{Kind: ChangeRemoved, Path: itemPath, Old: plainValue(a[i])})
+		case i >= len(a):
+			*changes = append(*changes, Change{Kind: ChangeAdded, Path: itemPath, New: plainValue(b[i])})
+		default:
+			diffValues(changes, itemPath, a[i], b[i])
+		}
+	}
+}
+
+func diffKeyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// equalScalars compares two decoded primitives. Number literals compare by
+// exact value, and NaN is treated as equal to itself.
+func equalScalars(a, b any) bool {
+	if x, ok := a.(numberLiteral); ok {
+		if y, ok := b.(numberLiteral); ok {
+			rx, okx := new(big.Rat).SetString(x.text)
+			ry, oky := new(big.Rat).SetString(y.text)
+			if okx && oky {
+				return rx.Cmp(ry) == 0
+			}
+			return x.value == y.value
+		}
+	}
+	x, okx := toFloat64(a)
+	y, oky := toFloat64(b)
+	if okx && oky {
+		return x == y || (math.IsNaN(x) && math.IsNaN(y))
+	}
+	if okx || oky {
+		return false
+	}
+	return a == b
+}
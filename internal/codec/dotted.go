@@ -0,0 +1,158 @@
+package codec
+
+import (
+	"fmt"
+	"strings"
+
+	formatpkg "github.com/toon-format/toon-go/internal/format"
+)
+
+// setField stores value under key in obj. With WithDottedKeyExpansion enabled,
+// unquoted keys such as a.b.c expand into nested objects and are deep-merged
+// with objects already present at the same path.
+func (p *parser) setField(obj *objectBuilder, key string, quoted bool, value any) error {
+	if err := p.checkKey(key); err != nil {
+		return err
+	}
+	if p.cfg.lint != nil && !p.cfg.expandDottedKeys {
+		if _, exists := obj.get(key); exists {
+			return fmt.Errorf("duplicate key %q", key)
+		}
+	}
+	if !p.cfg.expandDottedKeys {
+		obj.set(key, value)
+		return nil
+	}
+	path := []string{key}
+	if !quoted {
+		if segments, ok := splitDottedKey(key); ok {
+			path = segments
+			for _, segment := range segments {
+				if err := p.checkKey(segment); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return p.mergeField(obj, path, value)
+}
+
+// mergeField places value at path below obj. Objects meeting at the same path
+// are merged field by field; any other collision is an error in strict mode
+// and resolved in favour of the later value otherwise.
+func (p *parser) mergeField(obj *objectBuilder, path []string, value any) error {
+	key := path[0]
+	existing, exists := obj.get(key)
+	if len(path) > 1 {
+		child, ok := p.toObjectBuilder(existing)
+		if !exists || (!ok && !p.cfg.strict) {
+			child, ok = p.newObject(), true
+		}
+		if !ok {
+			return fmt.Errorf("key %q conflicts with dotted key expansion", key)
+		}
+		obj.set(key, child)
+		obj.nested = true
+		return p.mergeField(child, path[1:], value)
+	}
+	if exists {
+		if child, ok := p.toObjectBuilder(existing); ok {
+			if incoming, ok := asObject(value); ok {
+				for _, field := range incoming.Fields {
+					if err := p.mergeField(child, []string{field.Key}, field.Value); err != nil {
+						return err
+					}
+				}
+				obj.set(key, child)
+				obj.nested = true
+				return nil
+			}
+		}
+		if p.cfg.strict {
+			return fmt.Errorf("key %q conflicts with dotted key expansion", key)
+		}
+	}
+	obj.set(key, value)
+	return nil
+}
+
+// toObjectBuilder reopens a decoded object so further fields can be merged
+// into it.
+func (p *parser) toObjectBuilder(value any) (*objectBuilder, bool) {
+	switch v := value.(type) {
+	case *objectBuilder:
+		return v, true
+	case map[string]any:
+		return &objectBuilder{fields: v}, true
+	case Object:
+		b := &objectBuilder{index: make(map[string]int, len(v.Fields))}
+		for _, field := range v.Fields {
+			b.set(field.Key, field.Value)
+		}
+		return b, true
+	default:
+		return nil, false
+	}
+}
+
+// splitDottedKey splits key on dots when every segment is a plain identifier.
+func splitDottedKey(key string) ([]string, bool) {
+	if !strings.Contains(key, ".") {
+		return nil, false
+	}
+	segments := strings.Split(key, ".")
+	for _, segment := range segments {
+		if !isFoldableSegment(segment) {
+			return nil, false
+		}
+	}
+	return segments, true
+}
+
+func isFoldableSegment(segment string) bool {
+	return formatpkg.IsValidUnquotedKey(segment) && !strings.Contains(segment, ".")
+}
+
+func isQuotedKey(content string) bool {
+	return strings.HasPrefix(content, "\"")
+}
+
+// foldField collapses a chain of single-field objects below field into one
+// dotted key when WithDottedKeyCollapse is enabled. Chains are left intact
+// when a sibling key already starts with the field's key and a dot, since the
+// expanded form would collide on decode. folded reports whether keys were
+// joined.
+func (s *encodeState) foldField(obj Object, field Field) (_ Field, folded bool) {
+	if !s.cfg.collapseDottedKeys || !isFoldableSegment(field.Key) {
+		return field, false
+	}
+	key, value := field.Key, field.Value
+	for {
+		child, ok := value.(Object)
+		if !ok || len(child.Fields) != 1 || !isFoldableSegment(child.Fields[0].Key) {
+			break
+		}
+		key += "." + child.Fields[0].Key
+		value = child.Fields[0].Value
+	}
+	if key == field.Key {
+		return field, false
+	}
+	for _, sibling := range obj.Fields {
+		if strings.HasPrefix(sibling.Key, field.Key+".") {
+			return field, false
+		}
+	}
+	return Field{Key: key, Value: value}, true
+}
+
+// fieldKey encodes the key of a field, which foldField reports as folded when
+// it joined several keys. With WithDottedKeyCollapse enabled, any other key
+// containing a dot is quoted, so that WithDottedKeyExpansion keeps it intact
+// on decode.
+func (s *encodeState) fieldKey(key string, folded bool) (string, error) {
+	if s.cfg.collapseDottedKeys && !folded && strings.Contains(key, ".") {
+		return formatpkg.QuoteString(key)
+	}
+	return encodeKey(key)
+}
@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"errors"
+	"iter"
+)
+
+// ElementIterator lazily decodes the elements of a document whose root is an
+// array. Elements are produced one at a time as the iteration advances, so
+// large arrays never need to be collected into a single slice. Obtain one with
+// Decoder.Elements.
+type ElementIterator struct {
+	data    []byte
+	decoder *Decoder
+	err     error
+}
+
+// Elements returns an iterator over the elements of the top-level array in
+// data. Parse errors stop the iteration and are reported by Err.
+func (d *Decoder) Elements(data []byte) *ElementIterator {
+	return &ElementIterator{data: data, decoder: d}
+}
+
+// All yields each element of the root array together with its index. Objects
+// are produced as map[string]any, as with Decode.
+func (it *ElementIterator) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		it.err = nil
+		p, header, err := it.decoder.rootArray(it.data)
+		if err != nil {
+			it.err = err
+			return
+		}
+		if len(header.inlineValues) > 0 {
+			values, err := p.parseInlineValues(header)
+			if err != nil {
+				it.err = err
+				return
+			}
+			for i, value := range values {
+				if !yield(i, value) {
+					return
+				}
+			}
+			it.err = p.expectEnd()
+			return
+		}
+		count := 0
+		for {
+			value, ok, err := p.nextElement(header, 0, count)
+			if err != nil {
+				it.err = err
+				return
+			}
+			if !ok {
+				break
+			}
+			if !yield(count, value) {
+				return
+			}
+			count++
+		}
+		if it.err = p.checkLength(header, count); it.err == nil {
+			it.err = p.expectEnd()
+		}
+	}
+}
+
+// Err returns the error, if any, that stopped the most recent iteration.
+func (it *ElementIterator) Err() error {
+	return it.err
+}
+
+// rootArray prepares a parser positioned after the header of a root array.
+func (d *Decoder) rootArray(data []byte) (*parser, parsedHeader, error) {
+	if d.cfg.maxInputBytes > 0 && len(data) > d.cfg.maxInputBytes {
+		return nil, parsedHeader{}, errInputTooLarge(d.cfg.maxInputBytes)
+	}
+	p, err := newParser(string(data), d.cfg)
+	if err != nil {
+		return nil, parsedHeader{}, err
+	}
+	p.skipBlankLinesOutsideArrays()
+	if p.pos >= len(p.lines) {
+		return nil, parsedHeader{}, errors.New("toon: document root is not an array")
+	}
+	first := p.current()
+	header, ok, err := tryParseHeader(first.content)
+	if err != nil {
+		return nil, parsedHeader{}, errorWrap(first.number, err)
+	}
+	if !ok || first.indent != 0 || !header.keyless() {
+		return nil, parsedHeader{}, errors.New("toon: document root is not an array")
+	}
+	p.pos++
+	return p, header, nil
+}
@@ -2,8 +2,11 @@ package codec
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Encoder serializes Go values as TOON documents.
@@ -25,16 +28,49 @@ func NewEncoder(opts ...EncoderOption) *Encoder {
 // TOON data model (Section 2), then encoded using the concrete syntax rules
 // in Sections 5–12.
 func (e *Encoder) Marshal(v any) ([]byte, error) {
-	normalized, err := normalize(v, e.cfg)
+	state, err := e.encode(v)
 	if err != nil {
 		return nil, err
 	}
-	state := &encodeState{cfg: e.cfg}
+	return state.appendTo(make([]byte, 0, state.size())), nil
+}
+
+// AppendMarshal appends the TOON document for v to dst and returns the
+// extended slice. The appended bytes are identical to the output of Marshal,
+// so a buffer can be reused across calls by passing buf[:0].
+func (e *Encoder) AppendMarshal(dst []byte, v any) ([]byte, error) {
+	state, err := e.encode(v)
+	if err != nil {
+		return dst, err
+	}
+	return state.appendTo(dst), nil
+}
+
+func (e *Encoder) encode(v any) (*encodeState, error) {
+	cfg := e.cfg
+	if cfg.deterministic {
+		cfg = cfg.pinned()
+	}
+	if cfg.fieldFilter != nil {
+		cfg.pruned = new(int)
+	}
+	state := &encodeState{cfg: cfg}
+	if cfg.tabularFillValue != nil {
+		fill, err := normalizeFillValue(cfg.tabularFillValue)
+		if err != nil {
+			return nil, err
+		}
+		state.fill = fill
+	}
+	normalized, err := normalize(v, cfg)
+	if err != nil {
+		return nil, err
+	}
+	state.emitSchemaHeader()
 	if err := state.encodeRoot(normalized); err != nil {
 		return nil, err
 	}
-	output := strings.Join(state.lines, "\n")
-	return []byte(output), nil
+	return state, nil
 }
 
 // MarshalString is equivalent to Marshal but returns a string.
@@ -47,6 +83,39 @@ func (e *Encoder) MarshalString(v any) (string, error) {
 }
 
 // Marshal encodes v using a temporary encoder.
+//
+// A struct field tagged `toon:"name,prec=N"` rounds its value, or each number
+// in its array, to N decimal places. Rounded numbers are still written in
+// canonical form, so 1.50 is rendered as 1.5.
+//
+// A field tagged `toon:"name,method=Get"` is encoded from the result of
+// calling its Get method, which must take no arguments and return one value.
+// This also applies to unexported fields, which are otherwise skipped; they
+// are never decoded.
+//
+// A struct whose only field is a slice or array tagged `toon:",root"` encodes
+// as that array, exactly as if the slice were marshaled directly, and
+// Unmarshal fills the field from a root array. Tagging a field root in a
+// struct with other encoded fields is an error.
+//
+// Byte slices and arrays encode as arrays of numbers by default. A field
+// tagged `toon:"digest,hex"` encodes as a lowercase hex string instead, and
+// Unmarshal decodes the string back; prefer it for hashes and identifiers,
+// where the text is far shorter and matches how such values are displayed.
+//
+// An untagged embedded interface whose dynamic value is a struct, or a pointer
+// to one, has that struct's fields promoted into the enclosing object; fields
+// of the enclosing struct take precedence on name clashes. Any other dynamic
+// value, including nil, is encoded under the interface's type name. Unmarshal
+// cannot reverse the promotion, since the concrete type is unknown: to decode
+// such a shape, give the field a tag name so it encodes as a nested object,
+// and register its concrete types with RegisterType.
+//
+// Iterators are encoded without building a slice first: an iter.Seq becomes
+// an array, and an iter.Seq2 with string keys an object whose fields follow
+// the order of iteration. Since an array header declares its length, the
+// sequence is read to the end before any output is produced; it is iterated
+// once per call, so single-use sequences can only be marshaled once.
 func Marshal(v any, opts ...EncoderOption) ([]byte, error) {
 	return NewEncoder(opts...).Marshal(v)
 }
@@ -57,14 +126,62 @@ func MarshalString(v any, opts ...EncoderOption) (string, error) {
 }
 
 type encodeState struct {
-	cfg   encoderOptions
-	lines []string
+	cfg           encoderOptions
+	lines         []string
+	tabularArrays int
+	// fill is the normalized placeholder of WithTabularFillValue, validated
+	// once per document.
+	fill normalizedValue
+}
+
+// size returns the length of the document, with lines joined by newlines.
+func (s *encodeState) size() int {
+	if len(s.lines) == 0 {
+		return 0
+	}
+	n := len(s.lines) - 1
+	for _, line := range s.lines {
+		n += len(line)
+	}
+	return n
+}
+
+// appendTo appends the document to dst.
+func (s *encodeState) appendTo(dst []byte) []byte {
+	dst = slices.Grow(dst, s.size())
+	for i, line := range s.lines {
+		if i > 0 {
+			dst = append(dst, '\n')
+		}
+		dst = append(dst, line...)
+	}
+	return dst
 }
 
 func (s *encodeState) emit(line string) {
 	s.lines = append(s.lines, line)
 }
 
+// emitSchemaHeader writes each line of the WithSchemaHeader text as a "# "
+// comment line.
+func (s *encodeState) emitSchemaHeader() {
+	if s.cfg.schemaHeader == "" {
+		return
+	}
+	for _, line := range strings.Split(s.cfg.schemaHeader, "\n") {
+		s.emit(strings.TrimRight("# "+line, " "))
+	}
+}
+
+// lengthMarker reports whether an array header at depth carries the #
+// length marker.
+func (s *encodeState) lengthMarker(depth int) bool {
+	if s.cfg.lengthMarkersFunc != nil {
+		return s.cfg.lengthMarkersFunc(depth)
+	}
+	return s.cfg.includeLengthMarks
+}
+
 func (s *encodeState) indent(depth int) string {
 	if depth <= 0 {
 		return ""
@@ -75,10 +192,23 @@ func (s *encodeState) indent(depth int) string {
 func (s *encodeState) encodeRoot(value normalizedValue) error {
 	switch val := value.(type) {
 	case nil, bool, string, numberValue:
+		// A root string equal to the stream separator, or starting with "#"
+		// below a schema header, would not read back as itself, so it is
+		// quoted.
+		if str, ok := val.(string); ok && (str == documentSeparator || s.cfg.schemaHeader != "" && strings.HasPrefix(str, "#")) {
+			token, err := QuoteString(str)
+			if err != nil {
+				return err
+			}
+			s.emit(token)
+			return nil
+		}
 		token, err := formatPrimitive(val, formatContext{
-			active:   s.cfg.arrayDelimiter,
-			document: s.cfg.documentDelimiter,
-			inArray:  false,
+			active:       s.cfg.arrayDelimiter,
+			document:     s.cfg.documentDelimiter,
+			inArray:      false,
+			nullLiteral:  s.cfg.nullLiteral,
+			numericBools: s.cfg.numericBools,
 		})
 		if err != nil {
 			return err
@@ -93,109 +223,102 @@ func (s *encodeState) encodeRoot(value normalizedValue) error {
 			return err
 		}
 	default:
-		return fmt.Errorf("toon: unsupported root value %T", value)
+		return &UnsupportedTypeError{Type: reflect.TypeOf(value)}
 	}
 	return nil
 }
 
 func (s *encodeState) encodeObject(obj Object, depth int) error {
 	if depth == 0 && obj.IsEmpty() {
+		if s.cfg.emitEmptyObject {
+			s.emit(emptyObjectLiteral)
+		}
 		return nil
 	}
 	indent := s.indent(depth)
 	for _, field := range obj.Fields {
+		field, folded := s.foldField(obj, field)
+		keyLiteral, err := s.fieldKey(field.Key, folded)
+		if err != nil {
+			return err
+		}
 		switch val := field.Value.(type) {
 		case nil, bool, string, numberValue:
-			keyLiteral, err := encodeKey(field.Key)
-			if err != nil {
-				return err
-			}
 			token, err := formatPrimitive(val, formatContext{
-				active:   s.cfg.arrayDelimiter,
-				document: s.cfg.documentDelimiter,
-				inArray:  false,
+				active:       s.cfg.arrayDelimiter,
+				document:     s.cfg.documentDelimiter,
+				inArray:      false,
+				nullLiteral:  s.cfg.nullLiteral,
+				numericBools: s.cfg.numericBools,
 			})
 			if err != nil {
 				return err
 			}
 			s.emit(indent + keyLiteral + ": " + token)
 		case Object:
-			keyLiteral, err := encodeKey(field.Key)
-			if err != nil {
-				return err
-			}
 			s.emit(indent + keyLiteral + ":")
 			if err := s.encodeObject(val, depth+1); err != nil {
 				return err
 			}
 		case []normalizedValue:
-			if err := s.encodeArray(field.Key, val, depth, false); err != nil {
+			if err := s.encodeArray(keyLiteral, val, depth, false); err != nil {
 				return err
 			}
 		default:
-			return fmt.Errorf("toon: unsupported object field %s of type %T", field.Key, val)
+			return fmt.Errorf("toon: %s: %w", field.Key, &UnsupportedTypeError{Type: reflect.TypeOf(val)})
 		}
 	}
 	return nil
 }
 
-func (s *encodeState) encodeArray(key string, values []normalizedValue, depth int, root bool) error {
+// encodeArray writes values under keyLiteral, which is already encoded and
+// empty for a root array.
+func (s *encodeState) encodeArray(keyLiteral string, values []normalizedValue, depth int, root bool) error {
 	indent := s.indent(depth)
-	delimiter := s.cfg.arrayDelimiter
+	delimiter := s.delimiterFor(values, s.cfg.arrayDelimiter)
 	ctx := formatContext{
-		active:   delimiter,
-		document: s.cfg.documentDelimiter,
-		inArray:  true,
+		active:       delimiter,
+		document:     s.cfg.documentDelimiter,
+		inArray:      true,
+		nullLiteral:  s.cfg.nullLiteral,
+		numericBools: s.cfg.numericBools,
 	}
 
-	keyLiteral := ""
-	var err error
-	if key != "" {
-		keyLiteral, err = encodeKey(key)
-		if err != nil {
-			return err
-		}
-	}
+	cell := ctx
+	cell.escapeDelimiter = s.cfg.escapeDelimiters
 
 	if isPrimitiveArray(values) {
-		header := renderHeader(keyLiteral, len(values), delimiter, s.cfg.includeLengthMarks, nil)
+		header := renderHeader(keyLiteral, len(values), delimiter, s.lengthMarker(depth), nil)
 		line := indent + header
 		if len(values) > 0 {
-			inline := make([]string, 0, len(values))
-			for _, v := range values {
-				token, err := formatPrimitive(v, ctx)
-				if err != nil {
-					return err
-				}
-				inline = append(inline, token)
+			inline, err := joinCells(values, cell)
+			if err != nil {
+				return err
 			}
-			line += " " + strings.Join(inline, string(delimiter.rune()))
+			line += " " + inline
+		}
+		if s.fitsWidth(line) {
+			s.emit(line)
+			return nil
 		}
-		s.emit(line)
-		return nil
 	}
 
-	if fields, ok := detectTabular(values); ok {
-		header := renderHeader(keyLiteral, len(values), delimiter, s.cfg.includeLengthMarks, fields)
-		s.emit(indent + header)
-		for _, row := range values {
-			obj := row.(Object)
-			rowLine := s.indent(depth + 1)
-			rowValues := make([]string, 0, len(fields))
-			for _, field := range fields {
-				token, err := formatPrimitive(objField(obj, field), ctx)
-				if err != nil {
-					return err
-				}
-				rowValues = append(rowValues, token)
+	if fields, ok := s.tabularFields(values); ok {
+		rows, err := s.tabularRows(values, fields, depth+1, cell)
+		if err != nil {
+			return err
+		}
+		if rows != nil {
+			s.emit(indent + renderHeader(keyLiteral, len(values), delimiter, s.lengthMarker(depth), fields))
+			s.tabularArrays++
+			for _, row := range rows {
+				s.emit(row)
 			}
-			rowLine += strings.Join(rowValues, string(delimiter.rune()))
-			s.emit(rowLine)
+			return nil
 		}
-		return nil
 	}
 
-	header := renderHeader(keyLiteral, len(values), delimiter, s.cfg.includeLengthMarks, nil)
+	header := renderHeader(keyLiteral, len(values), delimiter, s.lengthMarker(depth), nil)
 	s.emit(indent + header)
 	for _, item := range values {
 		if root {
@@ -226,7 +349,7 @@ func (s *encodeState) encodeArrayItem(item normalizedValue, depth int, ctx forma
 	case []normalizedValue:
 		return s.encodeArrayForObjectListItem("", v, depth, ctx)
 	default:
-		return fmt.Errorf("toon: unsupported array item %T", v)
+		return &UnsupportedTypeError{Type: reflect.TypeOf(v)}
 	}
 	return nil
 }
@@ -246,22 +369,22 @@ func (s *encodeState) encodeListItem(item normalizedValue, depth int, ctx format
 	case []normalizedValue:
 		return s.encodeArrayForObjectListItem("", v, depth, ctx)
 	default:
-		return fmt.Errorf("toon: unsupported list item %T", v)
+		return &UnsupportedTypeError{Type: reflect.TypeOf(v)}
 	}
 	return nil
 }
 
 func (s *encodeState) encodeObjectListItem(obj Object, depth int, ctx formatContext) error {
 	if obj.IsEmpty() {
-		s.emit(s.indent(depth) + "- {}")
+		s.emit(s.indent(depth) + "- " + emptyObjectLiteral)
 		return nil
 	}
-	first := obj.Fields[0]
+	first, folded := s.foldField(obj, obj.Fields[0])
+	keyLiteral, err := s.fieldKey(first.Key, folded)
+	if err != nil {
+		return err
+	}
 	if isPrimitive(first.Value) {
-		keyLiteral, err := encodeKey(first.Key)
-		if err != nil {
-			return err
-		}
 		token, err := formatPrimitive(first.Value, ctx)
 		if err != nil {
 			return err
@@ -275,10 +398,6 @@ func (s *encodeState) encodeObjectListItem(obj Object, depth int, ctx formatCont
 		return nil
 	}
 	if arr, ok := first.Value.([]normalizedValue); ok {
-		keyLiteral, err := encodeKey(first.Key)
-		if err != nil {
-			return err
-		}
 		if err := s.encodeArrayForObjectListItem(keyLiteral, arr, depth, ctx); err != nil {
 			return err
 		}
@@ -289,52 +408,61 @@ func (s *encodeState) encodeObjectListItem(obj Object, depth int, ctx formatCont
 		}
 		return nil
 	}
-	s.emit(s.indent(depth) + "-")
-	return s.encodeObject(obj, depth+1)
+	nested, ok := first.Value.(Object)
+	if !ok {
+		return &UnsupportedTypeError{Type: reflect.TypeOf(first.Value)}
+	}
+	// The first field's object goes two levels below the hyphen so that the
+	// remaining fields, one level below, stay siblings of its key.
+	s.emit(s.indent(depth) + "- " + keyLiteral + ":")
+	if err := s.encodeObject(nested, depth+2); err != nil {
+		return err
+	}
+	if len(obj.Fields) > 1 {
+		return s.encodeObject(Object{Fields: obj.Fields[1:]}, depth+1)
+	}
+	return nil
 }
 
 func (s *encodeState) encodeArrayForObjectListItem(keyLiteral string, values []normalizedValue, depth int, ctx formatContext) error {
-	delimiter := ctx.active
+	delimiter := s.delimiterFor(values, ctx.active)
+	ctx.active = delimiter
 	indent := s.indent(depth)
+	cell := ctx
+	cell.escapeDelimiter = s.cfg.escapeDelimiters
 
-	if fields, ok := detectTabular(values); ok {
-		header := renderHeader(keyLiteral, len(values), delimiter, s.cfg.includeLengthMarks, fields)
-		s.emit(indent + "- " + header)
-		for _, row := range values {
-			obj := row.(Object)
-			rowLine := s.indent(depth + 1)
-			rowValues := make([]string, 0, len(fields))
-			for _, field := range fields {
-				token, err := formatPrimitive(objField(obj, field), ctx)
-				if err != nil {
-					return err
-				}
-				rowValues = append(rowValues, token)
+	if fields, ok := s.tabularFields(values); ok {
+		rows, err := s.tabularRows(values, fields, depth+1, cell)
+		if err != nil {
+			return err
+		}
+		if rows != nil {
+			s.emit(indent + "- " + renderHeader(keyLiteral, len(values), delimiter, s.lengthMarker(depth), fields))
+			s.tabularArrays++
+			for _, row := range rows {
+				s.emit(row)
 			}
-			s.emit(rowLine + strings.Join(rowValues, string(delimiter.rune())))
+			return nil
 		}
-		return nil
 	}
 
 	if isPrimitiveArray(values) {
-		header := renderHeader(keyLiteral, len(values), delimiter, s.cfg.includeLengthMarks, nil)
+		header := renderHeader(keyLiteral, len(values), delimiter, s.lengthMarker(depth), nil)
 		line := indent + "- " + header
 		if len(values) > 0 {
-			inline := make([]string, 0, len(values))
-			for _, v := range values {
-				token, err := formatPrimitive(v, ctx)
-				if err != nil {
-					return err
-				}
-				inline = append(inline, token)
+			inline, err := joinCells(values, cell)
+			if err != nil {
+				return err
 			}
-			line += " " + strings.Join(inline, string(delimiter.rune()))
+			line += " " + inline
+		}
+		if s.fitsWidth(line) {
+			s.emit(line)
+			return nil
 		}
-		s.emit(line)
-		return nil
 	}
 
-	header := renderHeader(keyLiteral, len(values), delimiter, s.cfg.includeLengthMarks, nil)
+	header := renderHeader(keyLiteral, len(values), delimiter, s.lengthMarker(depth), nil)
 	s.emit(indent + "- " + header)
 	for _, item := range values {
 		if err := s.encodeListItem(item, depth+1, ctx); err != nil {
@@ -344,28 +472,94 @@ func (s *encodeState) encodeArrayForObjectListItem(keyLiteral string, values []n
 	return nil
 }
 
-func detectTabular(values []normalizedValue) ([]string, bool) {
-	if len(values) == 0 {
-		return nil, false
-	}
-	first, ok := values[0].(Object)
-	if !ok || first.IsEmpty() {
-		return nil, false
+// joinCells formats values as delimiter-separated cells.
+func joinCells(values []normalizedValue, cell formatContext) (string, error) {
+	tokens := make([]string, 0, len(values))
+	for _, v := range values {
+		token, err := formatPrimitive(v, cell)
+		if err != nil {
+			return "", err
+		}
+		tokens = append(tokens, token)
 	}
-	fields := make([]string, len(first.Fields))
-	fieldSet := make(map[string]struct{}, len(first.Fields))
-	for i, field := range first.Fields {
-		if !isPrimitive(field.Value) {
-			return nil, false
+	return strings.Join(tokens, string(cell.active.rune())), nil
+}
+
+// tabularRows renders the rows of a tabular array at depth. It returns nil
+// when a row exceeds the maximum inline width, so that the caller falls back
+// to the list form.
+func (s *encodeState) tabularRows(values []normalizedValue, fields []string, depth int, cell formatContext) ([]string, error) {
+	rows := make([]string, 0, len(values))
+	cells := make([]normalizedValue, len(fields))
+	for _, row := range values {
+		obj, _ := row.(Object)
+		for i, field := range fields {
+			if row == nil {
+				cells[i] = s.fill
+				continue
+			}
+			cells[i] = objField(obj, field)
+		}
+		joined, err := joinCells(cells, cell)
+		if err != nil {
+			return nil, err
 		}
-		fields[i] = field.Key
-		fieldSet[field.Key] = struct{}{}
+		line := s.indent(depth) + joined
+		if !s.fitsWidth(line) {
+			return nil, nil
+		}
+		rows = append(rows, line)
 	}
-	for _, value := range values[1:] {
+	return rows, nil
+}
+
+// fitsWidth reports whether line respects WithMaxInlineWidth.
+func (s *encodeState) fitsWidth(line string) bool {
+	return s.cfg.maxInlineWidth <= 0 || utf8.RuneCountInString(line) <= s.cfg.maxInlineWidth
+}
+
+// tabularFields returns the header fields for values when they can be
+// written as a tabular array, sorted when WithSortTabularColumns is set.
+func (s *encodeState) tabularFields(values []normalizedValue) ([]string, bool) {
+	fields, ok := detectTabular(values, s.cfg.tabularFill)
+	if !ok && s.cfg.tabularThreshold > 0 {
+		fields, ok = detectSparseTabular(values, s.cfg.tabularFill, s.cfg.tabularThreshold)
+	}
+	if ok && s.cfg.sortTabularColumns {
+		slices.Sort(fields)
+	}
+	return fields, ok
+}
+
+// detectTabular reports the shared field list when every value is an object
+// with the same primitive-valued keys. When fillNil is set, nil values are
+// accepted as rows and later rendered as null cells.
+func detectTabular(values []normalizedValue, fillNil bool) ([]string, bool) {
+	var fields []string
+	var fieldSet map[string]struct{}
+	for _, value := range values {
+		if value == nil && fillNil {
+			continue
+		}
 		obj, ok := value.(Object)
 		if !ok {
 			return nil, false
 		}
+		if fields == nil {
+			if obj.IsEmpty() {
+				return nil, false
+			}
+			fields = make([]string, len(obj.Fields))
+			fieldSet = make(map[string]struct{}, len(obj.Fields))
+			for i, field := range obj.Fields {
+				if !isPrimitive(field.Value) {
+					return nil, false
+				}
+				fields[i] = field.Key
+				fieldSet[field.Key] = struct{}{}
+			}
+			continue
+		}
 		if len(obj.Fields) != len(fields) {
 			return nil, false
 		}
@@ -380,7 +574,90 @@ func detectTabular(values []normalizedValue) ([]string, bool) {
 			return nil, false
 		}
 	}
-	return fields, true
+	return fields, fields != nil
+}
+
+// detectSparseTabular reports the union of the fields of values, in
+// first-seen order, when every value is a non-empty object of primitive fields
+// and at least threshold of them carry the whole union, as WithTabularThreshold
+// allows. Missing fields are rendered as null cells.
+func detectSparseTabular(values []normalizedValue, fillNil bool, threshold float64) ([]string, bool) {
+	var fields []string
+	fieldSet := make(map[string]struct{})
+	var objects []Object
+	for _, value := range values {
+		if value == nil && fillNil {
+			continue
+		}
+		obj, ok := value.(Object)
+		if !ok || obj.IsEmpty() {
+			return nil, false
+		}
+		for _, field := range obj.Fields {
+			if !isPrimitive(field.Value) {
+				return nil, false
+			}
+			if _, ok := fieldSet[field.Key]; !ok {
+				fieldSet[field.Key] = struct{}{}
+				fields = append(fields, field.Key)
+			}
+		}
+		objects = append(objects, obj)
+	}
+	complete := 0
+	for _, obj := range objects {
+		if len(obj.Fields) == len(fields) {
+			complete++
+		}
+	}
+	return fields, len(objects) > 0 && float64(complete) >= threshold*float64(len(objects))
+}
+
+// delimiterFor returns the delimiter for the inline cells or tabular rows of
+// values. With WithAutoDelimiter it is the delimiter that the fewest string
+// cells contain, preferring fallback on ties.
+func (s *encodeState) delimiterFor(values []normalizedValue, fallback Delimiter) Delimiter {
+	if !s.cfg.autoDelimiter {
+		return fallback
+	}
+	best, fewest := fallback, countDelimited(values, fallback)
+	for _, delimiter := range []Delimiter{DelimiterComma, DelimiterTab, DelimiterPipe} {
+		if n := countDelimited(values, delimiter); n < fewest {
+			best, fewest = delimiter, n
+		}
+	}
+	return best
+}
+
+// countDelimited counts the string cells of values, including the primitive
+// fields of object rows, that contain delimiter.
+func countDelimited(values []normalizedValue, delimiter Delimiter) int {
+	count := 0
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			if strings.ContainsRune(v, delimiter.rune()) {
+				count++
+			}
+		case Object:
+			for _, field := range v.Fields {
+				if str, ok := field.Value.(string); ok && strings.ContainsRune(str, delimiter.rune()) {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// normalizeFillValue normalizes the placeholder of WithTabularFillValue and
+// WithDecoderTabularFillValue, which must be a primitive.
+func normalizeFillValue(value any) (normalizedValue, error) {
+	fill, err := normalize(value, encoderOptions{})
+	if err != nil || !isPrimitive(fill) {
+		return nil, fmt.Errorf("toon: tabular fill value must be a string, number or bool, got %T", value)
+	}
+	return fill, nil
 }
 
 func objField(obj Object, key string) normalizedValue {
@@ -1,27 +1,56 @@
 package codec
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
 
-type parseError struct {
-	line int
-	msg  string
+// ErrEmptyDocument is returned when WithErrorOnEmpty is enabled and the input
+// contains no content.
+var ErrEmptyDocument = errors.New("toon: empty document")
+
+// UnsupportedTypeError is returned by Marshal when a value, or a map key,
+// has a type that cannot be represented in TOON, such as a func or chan.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "toon: unsupported type " + e.Type.String()
+}
+
+// SyntaxError describes a problem in a TOON document at a 1-based line. The
+// decoder's parse errors have this type, and Lint returns a list of them.
+// Line is zero for problems that do not belong to a line, such as a size
+// limit.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e SyntaxError) Error() string {
+	if e.Line == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
 }
 
-func (e parseError) Error() string {
-	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+func errInputTooLarge(limit int) error {
+	return fmt.Errorf("toon: input exceeds %d bytes", limit)
 }
 
 func errorAt(line int, msg string) error {
-	return parseError{line: line, msg: msg}
+	return SyntaxError{Line: line, Msg: msg}
 }
 
 func errorAtf(line int, format string, args ...any) error {
-	return parseError{line: line, msg: fmt.Sprintf(format, args...)}
+	return SyntaxError{Line: line, Msg: fmt.Sprintf(format, args...)}
 }
 
 func errorWrap(line int, err error) error {
 	if err == nil {
 		return nil
 	}
-	return parseError{line: line, msg: err.Error()}
+	return SyntaxError{Line: line, Msg: err.Error()}
 }
@@ -0,0 +1,49 @@
+package codec
+
+import "crypto/sha256"
+
+// Fingerprint returns the SHA-256 hash of the canonical form of the TOON
+// document in data, so that documents with the same content share a
+// fingerprint. The canonical form is the document decoded and re-encoded with
+// the default encoder options: object keys sorted, two-space indentation and
+// comma delimiters. Numbers are compared by their exact decimal value as
+// written, so 1.0 and 1 match while integers beyond 2^53 that differ only past
+// float64 precision do not. Indentation, key order, delimiter choice and
+// quoting style therefore do not affect the result, while array order does.
+// opts describe how to parse data; options that only change the shape of
+// decoded values, such as WithOrderedObjects and WithUseNumber, are ignored.
+func Fingerprint(data []byte, opts ...DecoderOption) ([32]byte, error) {
+	value, err := NewDecoder(opts...).decode(data, true)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	canonical, err := Marshal(canonicalValue(value))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(canonical), nil
+}
+
+// canonicalValue converts a raw decoded value for Fingerprint: objects become
+// maps, whose keys Marshal sorts, and numbers take their canonical text.
+func canonicalValue(v any) any {
+	switch val := v.(type) {
+	case Object:
+		result := make(map[string]any, len(val.Fields))
+		for _, field := range val.Fields {
+			result[field.Key] = canonicalValue(field.Value)
+		}
+		return result
+	case []any:
+		result := make([]any, len(val))
+		for i, item := range val {
+			result[i] = canonicalValue(item)
+		}
+		return result
+	case numberLiteral:
+		val.text = canonicalNumber(val.text)
+		return val
+	default:
+		return v
+	}
+}
@@ -1,43 +1,101 @@
 package codec
 
 import (
-	"fmt"
+	"reflect"
 
 	formatpkg "github.com/toon-format/toon-go/internal/format"
 )
 
 type formatContext struct {
-	active   Delimiter
-	document Delimiter
-	inArray  bool
+	active      Delimiter
+	document    Delimiter
+	inArray     bool
+	nullLiteral string
+	// numericBools writes booleans as 1 and 0, for WithNumericBooleans.
+	numericBools bool
+	// escapeDelimiter is only set for inline and tabular cells, which the
+	// decoder splits on the delimiter; list items are read whole.
+	escapeDelimiter bool
 }
 
 func (c formatContext) toInternal() formatpkg.Context {
 	return formatpkg.Context{
-		Active:   c.active.rune(),
-		Document: c.document.rune(),
-		InArray:  c.inArray,
+		Active:          c.active.rune(),
+		Document:        c.document.rune(),
+		InArray:         c.inArray,
+		EscapeDelimiter: c.escapeDelimiter,
 	}
 }
 
 func formatPrimitive(value normalizedValue, ctx formatContext) (string, error) {
 	switch v := value.(type) {
 	case nil:
+		if ctx.nullLiteral != "" {
+			return ctx.nullLiteral, nil
+		}
 		return "null", nil
 	case bool:
-		if v {
+		switch {
+		case ctx.numericBools && v:
+			return "1", nil
+		case ctx.numericBools:
+			return "0", nil
+		case v:
 			return "true", nil
 		}
 		return "false", nil
 	case string:
+		if ctx.nullLiteral != "" && v == ctx.nullLiteral {
+			return formatpkg.QuoteString(v)
+		}
 		return formatpkg.FormatString(v, ctx.toInternal())
 	case numberValue:
 		return v.literal, nil
 	default:
-		return "", fmt.Errorf("toon: unsupported primitive %T", value)
+		return "", &UnsupportedTypeError{Type: reflect.TypeOf(value)}
+	}
+}
+
+// NeedsQuoting reports whether s must be quoted when emitted as a value. When
+// inArray is true, delimiter is the active array delimiter; otherwise it is the
+// document delimiter.
+func NeedsQuoting(s string, inArray bool, delimiter Delimiter) bool {
+	ctx := formatContext{inArray: inArray}
+	if inArray {
+		ctx.active = delimiter
+	} else {
+		ctx.document = delimiter
 	}
+	return formatpkg.NeedsQuoting(s, ctx.toInternal())
+}
+
+// QuoteString escapes s and wraps it in double quotes.
+func QuoteString(s string) (string, error) {
+	return formatpkg.QuoteString(s)
+}
+
+// EncodeKey renders key as an object key, quoting it when it is not a valid
+// unquoted identifier.
+func EncodeKey(key string) (string, error) {
+	return formatpkg.EncodeKey(key)
 }
 
 func encodeKey(key string) (string, error) {
 	return formatpkg.EncodeKey(key)
 }
+
+// validNullLiteral reports whether s can stand in for null: it must read back
+// as a bare token in every delimiter scope without colliding with the boolean,
+// null or numeric literals.
+func validNullLiteral(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, d := range []Delimiter{DelimiterComma, DelimiterTab, DelimiterPipe} {
+		ctx := formatContext{active: d, document: d, inArray: true}
+		if formatpkg.NeedsQuoting(s, ctx.toInternal()) {
+			return false
+		}
+	}
+	return true
+}
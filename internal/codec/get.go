@@ -0,0 +1,112 @@
+package codec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Get decodes data and returns the value at path, which uses the notation of
+// Diff: dotted keys with [index] suffixes, such as users[0].name, and "" for
+// the root. It fails when a key is missing, an index is out of range, or a
+// segment meets a value of the wrong type. Keys containing dots or brackets
+// cannot be addressed. With WithOrderedObjects, objects are walked and
+// returned as Object values.
+func Get(data []byte, path string, opts ...DecoderOption) (any, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := Decode(data, opts...)
+	if err != nil {
+		return nil, err
+	}
+	walked := ""
+	for _, seg := range segments {
+		if seg.key != "" {
+			var found bool
+			switch obj := value.(type) {
+			case map[string]any:
+				value, found = obj[seg.key]
+			case Object:
+				value, found = obj.Get(seg.key)
+			default:
+				return nil, fmt.Errorf("toon: %s is %s, not an object", pathName(walked), kindOf(value))
+			}
+			walked = diffKeyPath(walked, seg.key)
+			if !found {
+				return nil, fmt.Errorf("toon: %s not found", walked)
+			}
+			continue
+		}
+		items, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("toon: %s is %s, not an array", pathName(walked), kindOf(value))
+		}
+		if seg.index >= len(items) {
+			return nil, fmt.Errorf("toon: %s[%d] out of range for length %d", walked, seg.index, len(items))
+		}
+		walked += "[" + strconv.Itoa(seg.index) + "]"
+		value = items[seg.index]
+	}
+	return value, nil
+}
+
+// pathSegment is a key, or an array index when key is empty.
+type pathSegment struct {
+	key   string
+	index int
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := path
+	for rest != "" {
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("toon: invalid path %q: unclosed [", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 || !isDigits(rest[1:end]) {
+				return nil, fmt.Errorf("toon: invalid path %q: bad index %q", path, rest[1:end])
+			}
+			segments = append(segments, pathSegment{index: index})
+			rest = rest[end+1:]
+		} else {
+			if len(segments) > 0 {
+				if rest[0] != '.' {
+					return nil, fmt.Errorf("toon: invalid path %q: expected . or [ before %q", path, rest)
+				}
+				rest = rest[1:]
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("toon: invalid path %q: empty key", path)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+	return segments, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// pathName names path in errors, spelling out the root.
+func pathName(path string) string {
+	if path == "" {
+		return "root"
+	}
+	return path
+}
@@ -0,0 +1,60 @@
+package codec
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// GzipEncoder writes a gzip-compressed stream of TOON documents to an
+// io.Writer; Encode behaves as on WriterEncoder. Compressed data is buffered,
+// so Close must be called once encoding is done; until then the output is
+// truncated and cannot be decompressed. Close does not close the underlying
+// writer.
+type GzipEncoder struct {
+	*WriterEncoder
+	zw *gzip.Writer
+}
+
+// NewGzipEncoder returns an encoder that compresses its output to w with gzip
+// at the default compression level.
+func NewGzipEncoder(w io.Writer, opts ...EncoderOption) *GzipEncoder {
+	zw := gzip.NewWriter(w)
+	return &GzipEncoder{WriterEncoder: NewWriterEncoder(zw, opts...), zw: zw}
+}
+
+// Flush writes any buffered compressed data to the underlying writer without
+// ending the gzip stream, so that a reader can see the documents encoded so
+// far. It does not replace Close.
+func (e *GzipEncoder) Flush() error {
+	return e.zw.Flush()
+}
+
+// Close flushes buffered data and writes the gzip footer. It does not close
+// the underlying writer, and Encode must not be called afterwards.
+func (e *GzipEncoder) Close() error {
+	return e.zw.Close()
+}
+
+// GzipDecoder reads a gzip-compressed stream of TOON documents, such as the
+// output of GzipEncoder, one document at a time as ReaderDecoder does.
+// WithMaxInputBytes bounds the decompressed size of each document, which
+// guards against highly compressed input.
+type GzipDecoder struct {
+	*ReaderDecoder
+	zr *gzip.Reader
+}
+
+// NewGzipDecoder returns a decoder that decompresses r with gzip. It fails if
+// r does not begin with a valid gzip header.
+func NewGzipDecoder(r io.Reader, opts ...DecoderOption) (*GzipDecoder, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &GzipDecoder{ReaderDecoder: NewReaderDecoder(zr, opts...), zr: zr}, nil
+}
+
+// Close releases the gzip reader. It does not close the underlying reader.
+func (d *GzipDecoder) Close() error {
+	return d.zr.Close()
+}
@@ -0,0 +1,96 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// normalizeJSONMarshaler renders m through its MarshalJSON method and
+// normalizes the resulting JSON document, preserving object key order.
+func normalizeJSONMarshaler(m json.Marshaler, cfg encoderOptions) (normalizedValue, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("toon: MarshalJSON: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeOrderedJSON(dec)
+	if err != nil {
+		return nil, fmt.Errorf("toon: MarshalJSON output: %w", err)
+	}
+	return normalize(value, cfg)
+}
+
+// assignJSONUnmarshaler re-encodes the decoded subtree as JSON and hands it to
+// the destination's UnmarshalJSON method.
+func assignJSONUnmarshaler(dst reflect.Value, src any) error {
+	data, err := json.Marshal(exportValue(src, exportJSON))
+	if err != nil {
+		return fmt.Errorf("toon: %w", err)
+	}
+	u := dst.Addr().Interface().(json.Unmarshaler)
+	if err := u.UnmarshalJSON(data); err != nil {
+		return fmt.Errorf("toon: UnmarshalJSON: %w", err)
+	}
+	return nil
+}
+
+func implementsJSONUnmarshaler(dst reflect.Value) bool {
+	return dst.Kind() != reflect.Interface && dst.CanAddr() && dst.Addr().Type().Implements(jsonUnmarshalerType)
+}
+
+// decodeOrderedJSON reads a single JSON value, returning objects as Object so
+// that field order survives normalization.
+func decodeOrderedJSON(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token := tok.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			var fields []Field
+			for dec.More() {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyToken.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected string key, got %T", keyToken)
+				}
+				value, err := decodeOrderedJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, Field{Key: key, Value: value})
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return Object{Fields: fields}, nil
+		case '[':
+			items := []any{}
+			for dec.More() {
+				value, err := decodeOrderedJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return items, nil
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %v", token)
+		}
+	default:
+		return tok, nil
+	}
+}
@@ -0,0 +1,119 @@
+package codec
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// lintState carries Lint's progress through the parser: the recoverable
+// problems of the current pass, and the lines skipped to recover from
+// earlier errors.
+type lintState struct {
+	issues []SyntaxError
+	skip   map[int]bool
+}
+
+// report records err and returns nil when linting, so that parsing continues
+// past a problem that strict mode rejects. Otherwise it returns err.
+func (o decoderOptions) report(err error) error {
+	var syntaxErr SyntaxError
+	if o.lint == nil || !errors.As(err, &syntaxErr) {
+		return err
+	}
+	o.lint.issues = append(o.lint.issues, syntaxErr)
+	return nil
+}
+
+// Lint checks data as strict decoding does, but reports every problem it
+// finds instead of stopping at the first, for use by linters. Problems the
+// parser can continue past, such as length mismatches and indentation that is
+// not a multiple of the step, are recorded as they occur. Duplicate keys,
+// which Decode resolves in favour of the last value, are reported too. Any
+// other error skips the offending line, together with the lines nested below
+// it, and parsing restarts. The problems are ordered by line; Lint returns
+// nil for a valid document.
+func (d *Decoder) Lint(data []byte) []SyntaxError {
+	if d.cfg.maxInputBytes > 0 && len(data) > d.cfg.maxInputBytes {
+		return []SyntaxError{{Msg: errInputTooLarge(d.cfg.maxInputBytes).Error()}}
+	}
+	input := string(data)
+	state := &lintState{skip: map[int]bool{}}
+	cfg := d.cfg
+	cfg.lint = state
+	var fatal []SyntaxError
+	for {
+		state.issues = nil
+		err := lintPass(input, cfg)
+		if err == nil {
+			break
+		}
+		var syntaxErr SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			syntaxErr = SyntaxError{Msg: err.Error()}
+		}
+		fatal = append(fatal, syntaxErr)
+		if !state.skipBlock(input, syntaxErr.Line) {
+			break
+		}
+	}
+	issues := append(fatal, state.issues...)
+	slices.SortStableFunc(issues, func(a, b SyntaxError) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+	return issues
+}
+
+// Lint reports every problem in data using a temporary decoder.
+func Lint(data []byte, opts ...DecoderOption) []SyntaxError {
+	return NewDecoder(opts...).Lint(data)
+}
+
+func lintPass(input string, cfg decoderOptions) error {
+	p, err := newParser(input, cfg)
+	if err != nil {
+		return err
+	}
+	_, err = p.parseDocument()
+	return err
+}
+
+// skipBlock marks line, and the lines indented below it, as skipped. It
+// reports false when line is not a content line that is still parsed, so
+// there is nothing left to recover by skipping.
+func (s *lintState) skipBlock(input string, line int) bool {
+	if line == 0 || s.skip[line] {
+		return false
+	}
+	number, depth := 0, -1
+	var pending []int
+	for raw, rest, ok := nextLine(input); ok; raw, rest, ok = nextLine(rest) {
+		number++
+		if number < line {
+			continue
+		}
+		trimmed := strings.TrimLeft(raw, " \t")
+		if trimmed == "" {
+			if depth < 0 {
+				return false
+			}
+			pending = append(pending, number)
+			continue
+		}
+		indent := len(raw) - len(trimmed)
+		switch {
+		case depth < 0:
+			depth = indent
+		case indent <= depth:
+			return true
+		default:
+			for _, blank := range pending {
+				s.skip[blank] = true
+			}
+			pending = pending[:0]
+		}
+		s.skip[number] = true
+	}
+	return depth >= 0
+}
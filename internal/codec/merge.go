@@ -0,0 +1,109 @@
+package codec
+
+import "fmt"
+
+// ArrayMergeStrategy selects how Merge combines two arrays found at the same
+// path.
+type ArrayMergeStrategy int
+
+const (
+	// ArrayMergeReplace keeps the override array and discards the base array.
+	ArrayMergeReplace ArrayMergeStrategy = iota
+	// ArrayMergeAppend concatenates the override array onto the base array.
+	ArrayMergeAppend
+)
+
+// MergeOption mutates merge behaviour.
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	arrays   ArrayMergeStrategy
+	decoding []DecoderOption
+	encoding []EncoderOption
+}
+
+// WithArrayMerge selects how arrays present in both documents are combined.
+// The default is ArrayMergeReplace.
+func WithArrayMerge(strategy ArrayMergeStrategy) MergeOption {
+	return func(o *mergeOptions) {
+		o.arrays = strategy
+	}
+}
+
+// WithMergeDecoderOptions configures how both input documents are decoded.
+func WithMergeDecoderOptions(opts ...DecoderOption) MergeOption {
+	return func(o *mergeOptions) {
+		o.decoding = append(o.decoding, opts...)
+	}
+}
+
+// WithMergeEncoderOptions configures how the merged document is encoded.
+func WithMergeEncoderOptions(opts ...EncoderOption) MergeOption {
+	return func(o *mergeOptions) {
+		o.encoding = append(o.encoding, opts...)
+	}
+}
+
+// Merge deep-merges the TOON document override onto base and returns the
+// result as TOON. Objects are merged key by key: keys keep their position in
+// base, and keys only present in override follow in override order. Arrays
+// are combined according to WithArrayMerge. In every other case, including
+// type mismatches such as an object meeting a scalar, the override value
+// replaces the base value; a null in override therefore sets the value to
+// null rather than removing the key. Numbers keep their source text, so large
+// integers and decimals such as 1.10 are written back unchanged.
+func Merge(base, override []byte, opts ...MergeOption) ([]byte, error) {
+	var cfg mergeOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	dec := NewDecoder(cfg.decoding...)
+	baseValue, err := dec.decode(base, true)
+	if err != nil {
+		return nil, fmt.Errorf("toon: merge base: %w", err)
+	}
+	overrideValue, err := dec.decode(override, true)
+	if err != nil {
+		return nil, fmt.Errorf("toon: merge override: %w", err)
+	}
+	merged := mergeValues(baseValue, overrideValue, cfg.arrays)
+	return Marshal(merged, cfg.encoding...)
+}
+
+func mergeValues(base, override any, arrays ArrayMergeStrategy) any {
+	switch over := override.(type) {
+	case Object:
+		obj, ok := base.(Object)
+		if !ok {
+			return override
+		}
+		return mergeObjects(obj, over, arrays)
+	case []any:
+		items, ok := base.([]any)
+		if !ok || arrays == ArrayMergeReplace {
+			return override
+		}
+		merged := make([]any, 0, len(items)+len(over))
+		merged = append(merged, items...)
+		return append(merged, over...)
+	default:
+		return override
+	}
+}
+
+func mergeObjects(base, override Object, arrays ArrayMergeStrategy) Object {
+	fields := append([]Field(nil), base.Fields...)
+	index := make(map[string]int, len(fields))
+	for i, field := range fields {
+		index[field.Key] = i
+	}
+	for _, field := range override.Fields {
+		if i, ok := index[field.Key]; ok {
+			fields[i].Value = mergeValues(fields[i].Value, field.Value, arrays)
+			continue
+		}
+		index[field.Key] = len(fields)
+		fields = append(fields, field)
+	}
+	return Object{Fields: fields}
+}
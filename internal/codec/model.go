@@ -9,6 +9,21 @@ type numberValue struct {
 	literal string
 }
 
+// numberLiteral is a decoded number that keeps its source text so Unmarshal
+// can assign integers exactly instead of through float64.
+type numberLiteral struct {
+	value float64
+	text  string
+}
+
 // maxSafeInteger mirrors JavaScript's Number.MAX_SAFE_INTEGER, the threshold at
 // which IEEE 754 double precision can no longer represent integers exactly.
 const maxSafeInteger = 9007199254740991
+
+// emptyObjectLiteral is the token written for an empty object where a line of
+// its own is required: list items and, with WithEmitEmptyObject, the root.
+const emptyObjectLiteral = "{}"
+
+// emptyArrayLiteral is accepted by the decoder as an empty array wherever a
+// value stands alone. The encoder writes empty arrays as "[0]:" headers.
+const emptyArrayLiteral = "[]"
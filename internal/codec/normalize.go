@@ -1,14 +1,20 @@
 package codec
 
 import (
+	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
 	"reflect"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	formatpkg "github.com/toon-format/toon-go/internal/format"
 )
 
 // normalize applies the data-model rules from Section 2 and Section 3 to a Go
@@ -25,6 +31,12 @@ func normalize(v any, cfg encoderOptions) (normalizedValue, error) {
 	if v == nil {
 		return nil, nil
 	}
+	// A typed nil pointer boxed in an interface is null too. Handling it here
+	// keeps it away from method-based cases such as fmt.Stringer, whose
+	// methods may dereference the receiver.
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
 
 	switch val := v.(type) {
 	case string:
@@ -32,27 +44,20 @@ func normalize(v any, cfg encoderOptions) (normalizedValue, error) {
 	case bool:
 		return val, nil
 	case json.Number:
-		return normalizeNumberString(val.String())
+		return normalizeNumberString(val.String(), cfg.lossless)
+	case numberLiteral:
+		// Decoded numbers are written back with their source text, so that
+		// documents rewritten by Merge and Set keep untouched numbers byte for byte.
+		return numberValue{literal: val.text}, nil
 	case float32:
-		return normalizeFloat(float64(val))
+		return normalizeFloat(float64(val), cfg.lossless)
 	case float64:
-		return normalizeFloat(val)
+		return normalizeFloat(val, cfg.lossless)
 	case int, int8, int16, int32, int64:
-		i := reflect.ValueOf(val).Int()
-		if i > maxSafeInteger || i < -maxSafeInteger {
-			return strconv.FormatInt(i, 10), nil
-		}
-		return numberValue{literal: strconv.FormatInt(i, 10)}, nil
+		return normalizeInt(reflect.ValueOf(val).Int()), nil
 	case uint, uint8, uint16, uint32, uint64:
-		u := reflect.ValueOf(val).Uint()
-		if u > maxSafeInteger {
-			return strconv.FormatUint(u, 10), nil
-		}
-		return numberValue{literal: strconv.FormatUint(u, 10)}, nil
+		return normalizeUint(reflect.ValueOf(val).Uint()), nil
 	case *big.Int:
-		if val == nil {
-			return nil, nil
-		}
 		if val.IsInt64() {
 			return normalize(val.Int64(), cfg)
 		}
@@ -60,7 +65,9 @@ func normalize(v any, cfg encoderOptions) (normalizedValue, error) {
 	case big.Int:
 		return normalize(&val, cfg)
 	case time.Time:
-		return cfg.timeFormatter(val), nil
+		return cfg.formatTime(val), nil
+	case driver.Valuer:
+		return normalizeValuer(val, cfg)
 	case fmt.Stringer:
 		return val.String(), nil
 	case Object:
@@ -70,17 +77,38 @@ func normalize(v any, cfg encoderOptions) (normalizedValue, error) {
 	}
 
 	val := reflect.ValueOf(v)
+	if cfg.jsonBridge {
+		if m, ok := v.(json.Marshaler); ok {
+			return normalizeJSONMarshaler(m, cfg)
+		}
+	}
+	// Registered structs are looked up only once the method hooks above have
+	// had their say, so a registered type with a String method keeps it.
+	if cfg.typeDiscriminator != "" && reflect.Indirect(val).Kind() == reflect.Struct {
+		if name, ok := registeredName(val.Type()); ok {
+			return normalizeRegistered(val, name, cfg)
+		}
+	}
 	switch val.Kind() {
 	case reflect.Pointer:
-		if val.IsNil() {
-			return nil, nil
-		}
 		return normalize(val.Elem().Interface(), cfg)
+	// Defined scalar types such as "type UserID int64" miss the concrete
+	// cases above and get the same treatment here.
+	case reflect.Bool:
+		return val.Bool(), nil
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return normalizeInt(val.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return normalizeUint(val.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return normalizeFloat(val.Float(), cfg.lossless)
 	case reflect.Slice, reflect.Array:
 		length := val.Len()
 		result := make([]normalizedValue, 0, length)
 		for i := 0; i < length; i++ {
-			item, err := normalize(val.Index(i).Interface(), cfg)
+			item, err := normalize(val.Index(i).Interface(), cfg.enterIndex(i))
 			if err != nil {
 				return nil, err
 			}
@@ -89,80 +117,277 @@ func normalize(v any, cfg encoderOptions) (normalizedValue, error) {
 		return result, nil
 	case reflect.Map:
 		if val.Type().Key().Kind() != reflect.String {
-			return nil, fmt.Errorf("toon: unsupported map key type %s", val.Type().Key())
+			return nil, &UnsupportedTypeError{Type: val.Type()}
 		}
 		iter := val.MapRange()
 		var fields []Field
 		for iter.Next() {
-			fieldValue, err := normalize(iter.Value().Interface(), cfg)
+			fieldCfg, keep := cfg.enterField(iter.Key().String())
+			if !keep {
+				continue
+			}
+			mark := cfg.prunedMark()
+			fieldValue, err := normalize(iter.Value().Interface(), fieldCfg)
 			if err != nil {
 				return nil, err
 			}
+			if cfg.emptiedSince(mark, fieldValue) {
+				continue
+			}
 			fields = append(fields, Field{
 				Key:   iter.Key().String(),
 				Value: fieldValue,
 			})
 		}
-		slices.SortFunc(fields, func(a, b Field) int {
-			if a.Key < b.Key {
-				return -1
+		compare := strings.Compare
+		switch {
+		case cfg.mapKeyCompare != nil:
+			custom := cfg.mapKeyCompare
+			compare = func(a, b string) int {
+				if c := custom(a, b); c != 0 {
+					return c
+				}
+				return strings.Compare(a, b)
 			}
-			if a.Key > b.Key {
-				return 1
-			}
-			return 0
+		case cfg.naturalKeyOrder:
+			compare = naturalCompare
+		}
+		slices.SortFunc(fields, func(a, b Field) int {
+			return compare(a.Key, b.Key)
 		})
 		return Object{Fields: fields}, nil
 	case reflect.Struct:
+		field, ok, err := cachedStructMeta(val.Type()).rootField(val.Type())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return normalizeRootField(val, field, cfg)
+		}
 		return normalizeStructValue(val, cfg)
+	case reflect.Func:
+		if arity := seqArity(val.Type()); arity > 0 {
+			if val.IsNil() {
+				return nil, nil
+			}
+			return normalizeSeq(val, arity, cfg)
+		}
 	}
 
-	return nil, fmt.Errorf("toon: unsupported value of type %T", v)
+	return nil, &UnsupportedTypeError{Type: val.Type()}
 }
 
 func normalizeStructValue(val reflect.Value, cfg encoderOptions) (Object, error) {
 	meta := cachedStructMeta(val.Type())
 	fields := make([]Field, 0, len(meta.fields))
+	var shadowed map[string]bool
 	for _, field := range meta.fields {
-		childValue := fieldValueByIndex(val, field.index)
+		// Promoted fields are filtered by their own names, at the level of
+		// the struct that embeds them.
+		fieldCfg, keep := cfg, true
+		if !field.promote {
+			fieldCfg, keep = cfg.enterField(field.name)
+		}
+		if !keep {
+			continue
+		}
+		childValue := reflect.Value{}
+		if field.method != "" {
+			var err error
+			if childValue, err = fieldValueByMethod(val, field); err != nil {
+				return Object{}, fmt.Errorf("toon: %s: %w", field.name, err)
+			}
+		} else {
+			childValue = fieldValueByIndex(val, field.index)
+		}
 		if field.omitEmpty && isEmptyValue(childValue) {
 			continue
 		}
-		child, err := normalize(childValue.Interface(), cfg)
+		if field.omitZero && isZeroValue(childValue) {
+			continue
+		}
+		var child normalizedValue
+		var err error
+		mark := cfg.prunedMark()
+		if field.hex {
+			child, err = normalizeHex(childValue)
+		} else {
+			child, err = normalize(childValue.Interface(), fieldCfg)
+		}
 		if err != nil {
 			return Object{}, fmt.Errorf("toon: %s: %w", field.name, err)
 		}
+		if !field.promote && cfg.emptiedSince(mark, child) {
+			continue
+		}
+		if field.precision >= 0 {
+			child = roundNumbers(child, field.precision)
+		}
+		if obj, ok := child.(Object); ok && field.promote && isStructValue(childValue) {
+			if shadowed == nil {
+				shadowed = make(map[string]bool, len(meta.fields))
+				for _, f := range meta.fields {
+					if !f.promote {
+						shadowed[f.name] = true
+					}
+				}
+			}
+			// Fields of the struct itself shadow promoted ones, as in Go, and
+			// the first embedded interface wins among promoted fields.
+			for _, promoted := range obj.Fields {
+				if !shadowed[promoted.Key] {
+					shadowed[promoted.Key] = true
+					fields = append(fields, promoted)
+				}
+			}
+			continue
+		}
+		if field.promote {
+			if _, keep := cfg.enterField(field.name); !keep {
+				continue
+			}
+		}
 		fields = append(fields, Field{
 			Key:   field.name,
 			Value: child,
 		})
 	}
-	return Object{Fields: fields}, nil
+	return sortedFields(fields, cfg), nil
+}
+
+// isStructValue reports whether the dynamic value held by an interface is a
+// struct or a non-nil pointer to one.
+func isStructValue(v reflect.Value) bool {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct
+}
+
+// normalizeRootField normalizes the array held by a struct's root field in
+// place of the struct, so that the struct encodes as a root array.
+func normalizeRootField(val reflect.Value, field structFieldMeta, cfg encoderOptions) (normalizedValue, error) {
+	child := fieldValueByIndex(val, field.index)
+	if child.Kind() != reflect.Slice && child.Kind() != reflect.Array {
+		return nil, fmt.Errorf("toon: %s: root field %s must be a slice or array, got %s", val.Type(), field.name, child.Type())
+	}
+	return normalize(child.Interface(), cfg)
 }
 
 func normalizeObjectFields(fields []Field, cfg encoderOptions) (Object, error) {
 	normalized := make([]Field, 0, len(fields))
 	for _, field := range fields {
-		child, err := normalize(field.Value, cfg)
+		fieldCfg, keep := cfg.enterField(field.Key)
+		if !keep {
+			continue
+		}
+		mark := cfg.prunedMark()
+		child, err := normalize(field.Value, fieldCfg)
 		if err != nil {
 			return Object{}, fmt.Errorf("toon: %s: %w", field.Key, err)
 		}
+		if cfg.emptiedSince(mark, child) {
+			continue
+		}
 		normalized = append(normalized, Field{
 			Key:   field.Key,
 			Value: child,
 		})
 	}
-	return Object{Fields: normalized}, nil
+	return sortedFields(normalized, cfg), nil
+}
+
+// enterField returns the options for normalizing the value of the field key,
+// and reports false when WithFieldFilter rejects the field.
+func (o encoderOptions) enterField(key string) (encoderOptions, bool) {
+	if o.fieldFilter == nil {
+		return o, true
+	}
+	o.path = diffKeyPath(o.path, key)
+	if o.fieldFilter(o.path) {
+		return o, true
+	}
+	if o.pruned != nil {
+		*o.pruned++
+	}
+	return o, false
+}
+
+// enterIndex returns the options for normalizing the array element at index.
+func (o encoderOptions) enterIndex(index int) encoderOptions {
+	if o.fieldFilter != nil {
+		o.path += "[" + strconv.Itoa(index) + "]"
+	}
+	return o
+}
+
+// prunedMark returns the number of fields WithFieldFilter has dropped so far.
+func (o encoderOptions) prunedMark() int {
+	if o.pruned == nil {
+		return 0
+	}
+	return *o.pruned
+}
+
+// emptiedSince reports whether child is an object that WithFieldFilter left
+// without fields after mark, in which case it is dropped and counted too.
+func (o encoderOptions) emptiedSince(mark int, child normalizedValue) bool {
+	if o.pruned == nil || *o.pruned == mark {
+		return false
+	}
+	if obj, ok := child.(Object); ok && obj.IsEmpty() {
+		*o.pruned++
+		return true
+	}
+	return false
+}
+
+// sortedFields wraps fields in an Object, sorting them by key when
+// WithDeterministic is set. Other objects keep the field order of their
+// source.
+func sortedFields(fields []Field, cfg encoderOptions) Object {
+	if cfg.deterministic {
+		slices.SortStableFunc(fields, func(a, b Field) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+	}
+	return Object{Fields: fields}
 }
 
-func normalizeFloat(f float64) (normalizedValue, error) {
+// normalizeInt keeps i numeric when it is within ±maxSafeInteger and renders
+// it as a decimal string otherwise.
+func normalizeInt(i int64) normalizedValue {
+	if i > maxSafeInteger || i < -maxSafeInteger {
+		return strconv.FormatInt(i, 10)
+	}
+	return numberValue{literal: strconv.FormatInt(i, 10)}
+}
+
+func normalizeUint(u uint64) normalizedValue {
+	if u > maxSafeInteger {
+		return strconv.FormatUint(u, 10)
+	}
+	return numberValue{literal: strconv.FormatUint(u, 10)}
+}
+
+// normalizeFloat renders f in canonical form. NaN and infinities become null
+// and negative zero becomes 0, unless lossless is set, in which case they are
+// errors.
+func normalizeFloat(f float64, lossless bool) (normalizedValue, error) {
 	switch {
-	case math.IsNaN(f):
-		return nil, nil
-	case math.IsInf(f, 1), math.IsInf(f, -1):
+	case math.IsNaN(f), math.IsInf(f, 0):
+		if lossless {
+			return nil, fmt.Errorf("toon: lossless: cannot encode %v", f)
+		}
 		return nil, nil
 	default:
-		if f == math.Copysign(0, -1) {
+		if f == 0 && math.Signbit(f) {
+			if lossless {
+				return nil, errors.New("toon: lossless: cannot encode negative zero")
+			}
 			f = 0
 		}
 		s := strconv.FormatFloat(f, 'f', -1, 64)
@@ -170,17 +395,180 @@ func normalizeFloat(f float64) (normalizedValue, error) {
 	}
 }
 
-func normalizeNumberString(s string) (normalizedValue, error) {
+// roundNumbers rounds v to precision decimals when it is a number, or each
+// number element when it is an array. Other values are returned unchanged.
+// Rounded numbers keep the canonical form, so trailing zeros are dropped.
+func roundNumbers(v normalizedValue, precision int) normalizedValue {
+	switch val := v.(type) {
+	case numberValue:
+		f, err := strconv.ParseFloat(val.literal, 64)
+		if err != nil {
+			return val
+		}
+		rounded, _ := normalizeNumberString(strconv.FormatFloat(f, 'f', precision, 64), false)
+		return rounded
+	case []normalizedValue:
+		for i, item := range val {
+			if _, ok := item.(numberValue); ok {
+				val[i] = roundNumbers(item, precision)
+			}
+		}
+		return val
+	}
+	return v
+}
+
+// normalizeHex renders a byte slice or array as a lowercase hex string for a
+// field tagged with the hex option. A nil slice is encoded as null.
+func normalizeHex(val reflect.Value) (normalizedValue, error) {
+	if (val.Kind() != reflect.Slice && val.Kind() != reflect.Array) || val.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, fmt.Errorf("toon: hex option requires a byte slice or array, got %s", val.Type())
+	}
+	if val.Kind() == reflect.Slice && val.IsNil() {
+		return nil, nil
+	}
+	b := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(b), val)
+	return hex.EncodeToString(b), nil
+}
+
+// normalizeNumberString renders a json.Number in canonical form. Strings that
+// are not numbers are kept as strings, non-finite values become null, and
+// other literals are rewritten, so 1.0 becomes 1. With lossless set, each of
+// these changes is an error instead.
+func normalizeNumberString(s string, lossless bool) (normalizedValue, error) {
 	f, err := strconv.ParseFloat(s, 64)
 	if err != nil {
+		if lossless {
+			return nil, fmt.Errorf("toon: lossless: json.Number %q is not a number", s)
+		}
 		// Preserve as string literal; encoder will handle quoting.
 		return s, nil
 	}
 	if math.IsInf(f, 0) || math.IsNaN(f) {
+		if lossless {
+			return nil, fmt.Errorf("toon: lossless: cannot encode json.Number %q", s)
+		}
 		return nil, nil
 	}
 	if f == 0 {
 		f = 0
 	}
-	return numberValue{literal: strconv.FormatFloat(f, 'f', -1, 64)}, nil
+	literal := strconv.FormatFloat(f, 'f', -1, 64)
+	// Digits beyond float64 precision are kept when the exact value can be
+	// written without an exponent.
+	if formatpkg.LooksNumeric(s) {
+		if exact := canonicalNumber(s); !strings.Contains(exact, "e") {
+			literal = exact
+		}
+	}
+	if lossless && literal != s {
+		return nil, fmt.Errorf("toon: lossless: json.Number %q would be written as %s", s, literal)
+	}
+	return numberValue{literal: literal}, nil
+}
+
+// maxPlainExponent bounds the zeros canonicalNumber writes out in plain
+// decimal form; values further from 1 keep an exponent.
+const maxPlainExponent = 21
+
+// canonicalNumber rewrites a decimal literal so that literals of the same
+// value share one text: 1.50, 1.5 and 15e-1 all become 1.5. It works on the
+// digits alone, so no precision is lost. Text it cannot parse is returned
+// unchanged.
+func canonicalNumber(text string) string {
+	negative := strings.HasPrefix(text, "-")
+	mantissa, expText, hasExp := strings.Cut(strings.TrimPrefix(text, "-"), "e")
+	if !hasExp {
+		mantissa, expText, hasExp = strings.Cut(mantissa, "E")
+	}
+	exp := 0
+	if hasExp {
+		var err error
+		if exp, err = strconv.Atoi(expText); err != nil {
+			return text
+		}
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(whole+frac, "0")
+	exp -= len(frac)
+	if digits == "" {
+		return "0"
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	exp += len(digits) - len(trimmed)
+	digits = trimmed
+
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	switch {
+	case exp >= 0 && exp <= maxPlainExponent:
+		b.WriteString(digits)
+		b.WriteString(strings.Repeat("0", exp))
+	case exp < 0 && -exp < len(digits):
+		b.WriteString(digits[:len(digits)+exp])
+		b.WriteByte('.')
+		b.WriteString(digits[len(digits)+exp:])
+	case exp < 0 && -exp-len(digits) <= maxPlainExponent:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", -exp-len(digits)))
+		b.WriteString(digits)
+	default:
+		b.WriteString(digits)
+		b.WriteByte('e')
+		b.WriteString(strconv.Itoa(exp))
+	}
+	return b.String()
+}
+
+// naturalCompare orders strings like strings.Compare, except that runs of
+// ASCII digits compare by numeric value, so "item2" sorts before "item10".
+// Runs with equal values but different leading zeros fall back to byte order
+// to keep the ordering total.
+func naturalCompare(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			startA, startB := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			numA := strings.TrimLeft(a[startA:i], "0")
+			numB := strings.TrimLeft(b[startB:j], "0")
+			if len(numA) != len(numB) {
+				if len(numA) < len(numB) {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(numA, numB); c != 0 {
+				return c
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			if a[i] < b[j] {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+	if c := (len(a) - i) - (len(b) - j); c != 0 {
+		if c < 0 {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
 }
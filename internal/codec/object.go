@@ -1,5 +1,10 @@
 package codec
 
+import (
+	"fmt"
+	"slices"
+)
+
 // Field represents a single key/value pair in an ordered object.
 type Field struct {
 	Key   string
@@ -26,3 +31,106 @@ func (o Object) Len() int {
 func (o Object) IsEmpty() bool {
 	return len(o.Fields) == 0
 }
+
+// Get returns the value stored under key and whether the key is present.
+func (o Object) Get(key string) (any, bool) {
+	if i := o.index(key); i >= 0 {
+		return o.Fields[i].Value, true
+	}
+	return nil, false
+}
+
+// Has reports whether key is present.
+func (o Object) Has(key string) bool {
+	return o.index(key) >= 0
+}
+
+// Set stores value under key. An existing key keeps its position; a new key
+// is appended after the existing fields.
+func (o *Object) Set(key string, value any) {
+	if i := o.index(key); i >= 0 {
+		o.Fields[i].Value = value
+		return
+	}
+	o.Fields = append(o.Fields, Field{Key: key, Value: value})
+}
+
+// SetObject stores a nested object under key, as Set does, after build has
+// populated it. build starts from the object already stored under key, if
+// any, so repeated calls extend the same nested object.
+func (o *Object) SetObject(key string, build func(*Object)) {
+	var nested Object
+	if existing, ok := o.Get(key); ok {
+		nested, _ = existing.(Object)
+	}
+	build(&nested)
+	o.Set(key, nested)
+}
+
+// Delete removes key, keeping the order of the remaining fields. Deleting an
+// absent key does nothing.
+func (o *Object) Delete(key string) {
+	if i := o.index(key); i >= 0 {
+		o.Fields = append(o.Fields[:i], o.Fields[i+1:]...)
+	}
+}
+
+func (o Object) index(key string) int {
+	for i, field := range o.Fields {
+		if field.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// ObjectOption mutates how NewObjectFromMap builds an Object.
+type ObjectOption func(*objectOptions)
+
+type objectOptions struct {
+	missingAsNull bool
+}
+
+// WithMissingKeysAsNull makes NewObjectFromMap add a null field for each key
+// in keyOrder that the map lacks, instead of failing.
+func WithMissingKeysAsNull(enabled bool) ObjectOption {
+	return func(o *objectOptions) {
+		o.missingAsNull = enabled
+	}
+}
+
+// NewObjectFromMap builds an ordered Object from m. Keys listed in keyOrder
+// come first, in that order; the remaining keys follow sorted by byte order.
+// Repeated keys in keyOrder are used once. A listed key missing from m is an
+// error unless WithMissingKeysAsNull is set. Nested maps are stored as they
+// are, so the encoder sorts their keys.
+func NewObjectFromMap(m map[string]any, keyOrder []string, opts ...ObjectOption) (Object, error) {
+	var cfg objectOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	fields := make([]Field, 0, len(m))
+	placed := make(map[string]bool, len(keyOrder))
+	for _, key := range keyOrder {
+		if placed[key] {
+			continue
+		}
+		placed[key] = true
+		value, ok := m[key]
+		if !ok && !cfg.missingAsNull {
+			return Object{}, fmt.Errorf("toon: key %q in key order is not in the map", key)
+		}
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+	rest := make([]string, 0, len(m))
+	for key := range m {
+		if !placed[key] {
+			rest = append(rest, key)
+		}
+	}
+	slices.Sort(rest)
+	for _, key := range rest {
+		fields = append(fields, Field{Key: key, Value: m[key]})
+	}
+	return Object{Fields: fields}, nil
+}
@@ -51,7 +51,33 @@ type encoderOptions struct {
 	documentDelimiter  Delimiter
 	arrayDelimiter     Delimiter
 	includeLengthMarks bool
+	lengthMarkersFunc  func(depth int) bool
 	timeFormatter      func(time.Time) string
+	timeLocation       *time.Location
+	jsonBridge         bool
+	tabularFill        bool
+	tabularFillValue   any
+	sortTabularColumns bool
+	collapseDottedKeys bool
+	typeDiscriminator  string
+	nullLiteral        string
+	naturalKeyOrder    bool
+	mapKeyCompare      func(a, b string) int
+	emitEmptyObject    bool
+	escapeDelimiters   bool
+	maxInlineWidth     int
+	lossless           bool
+	deterministic      bool
+	numericBools       bool
+	schemaHeader       string
+	fieldFilter        func(path string) bool
+	autoDelimiter      bool
+	tabularThreshold   float64
+
+	// path locates the value being normalized, and pruned counts the fields
+	// dropped so far by fieldFilter. Both are only tracked with a filter.
+	path   string
+	pruned *int
 }
 
 func defaultEncoderOptions() encoderOptions {
@@ -59,12 +85,26 @@ func defaultEncoderOptions() encoderOptions {
 		indentSize:        2,
 		documentDelimiter: DelimiterComma,
 		arrayDelimiter:    DelimiterComma,
-		timeFormatter: func(t time.Time) string {
-			return t.UTC().Format(time.RFC3339Nano)
-		},
 	}
 }
 
+// formatTime renders t with the configured formatter, after converting it to
+// the configured location. Without either option, times are written in UTC as
+// RFC 3339 with nanoseconds; a custom formatter without a location receives t
+// unchanged.
+func (o encoderOptions) formatTime(t time.Time) string {
+	switch {
+	case o.timeLocation != nil:
+		t = t.In(o.timeLocation)
+	case o.timeFormatter == nil:
+		t = t.UTC()
+	}
+	if o.timeFormatter != nil {
+		return o.timeFormatter(t)
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
 // WithIndent configures the number of spaces used per indentation level.
 func WithIndent(spaces int) EncoderOption {
 	return func(o *encoderOptions) {
@@ -95,9 +135,21 @@ func WithArrayDelimiter(delimiter Delimiter) EncoderOption {
 }
 
 // WithLengthMarkers enables emitting optional # markers in array headers.
+// It replaces any predicate set by WithLengthMarkersFunc.
 func WithLengthMarkers(enabled bool) EncoderOption {
 	return func(o *encoderOptions) {
 		o.includeLengthMarks = enabled
+		o.lengthMarkersFunc = nil
+	}
+}
+
+// WithLengthMarkersFunc decides per array header whether to emit the #
+// marker. depth is the indentation level of the header line, so arrays at
+// the root of the document, or fields of the root object, have depth 0.
+// Passing nil restores the setting of WithLengthMarkers.
+func WithLengthMarkersFunc(fn func(depth int) bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.lengthMarkersFunc = fn
 	}
 }
 
@@ -110,23 +162,290 @@ func WithTimeFormatter(formatter func(time.Time) string) EncoderOption {
 	}
 }
 
+// WithTimeLocation converts time.Time values to loc before formatting, so
+// that the default formatter writes them with loc's offset, such as
+// 2025-10-31T08:00:00-04:00, instead of in UTC. The written instant is
+// unchanged and decodes back to an equal time.Time.
+func WithTimeLocation(loc *time.Location) EncoderOption {
+	return func(o *encoderOptions) {
+		o.timeLocation = loc
+	}
+}
+
+// WithJSONBridge makes values implementing json.Marshaler encode through
+// MarshalJSON. The bridge is consulted after the built-in handling of
+// time.Time, fmt.Stringer and Object values, but before reflection.
+func WithJSONBridge(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.jsonBridge = enabled
+	}
+}
+
+// WithTabularFill lets arrays of uniform objects that contain nil elements,
+// such as a []*T with nil entries, keep the tabular form by emitting a row of
+// null cells for each nil. Without it such arrays fall back to list form with
+// "- null" items.
+func WithTabularFill(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.tabularFill = enabled
+	}
+}
+
+// WithTabularFillValue sets the placeholder written in every cell of the rows
+// that WithTabularFill emits for nil elements, in place of null, so that they
+// cannot be mistaken for rows of genuine nulls. The value must be a string,
+// number or bool, or every encode fails, even of documents without nil rows;
+// nil restores null cells. Pick a placeholder that real rows never contain in
+// every cell, and decode with WithDecoderTabularFillValue to read such rows
+// back as nil.
+func WithTabularFillValue(value any) EncoderOption {
+	return func(o *encoderOptions) {
+		o.tabularFillValue = value
+	}
+}
+
+// WithFieldFilter encodes only the object fields for which keep returns true,
+// for projecting or redacting a value at run time. keep receives the path of
+// each struct field, map entry and Object field, in the notation of Diff:
+// dotted keys with [index] suffixes for array elements, such as
+// "users[0].email". A rejected field is skipped together with everything below
+// it, so keep must accept the parents of the fields it selects. An object left
+// without fields by the filter is dropped from its parent object as well,
+// rather than encoded empty; array elements keep their place.
+func WithFieldFilter(keep func(path string) bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.fieldFilter = keep
+	}
+}
+
+// WithSortTabularColumns sorts the fields of tabular array headers, and the
+// cells of each row, alphabetically instead of following the field order of
+// the first element. Output then no longer depends on how the rows were built.
+func WithSortTabularColumns(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.sortTabularColumns = enabled
+	}
+}
+
+// WithDottedKeyCollapse folds chains of single-field objects into dotted keys,
+// so {"a": {"b": {"c": 1}}} encodes as "a.b.c: 1". Only keys made of plain
+// identifier segments are folded, including in the objects of list items.
+// Keys that already contain a dot are quoted, so that decoding with
+// WithDottedKeyExpansion, which this option pairs with, restores them intact.
+func WithDottedKeyCollapse(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.collapseDottedKeys = enabled
+	}
+}
+
+// WithTypeDiscriminator makes values whose type was registered with
+// RegisterType encode with a leading key field naming the type, so that they
+// can be decoded back into interface-typed destinations. Registered types
+// must be structs or pointers to structs; those implementing fmt.Stringer,
+// driver.Valuer or, with WithJSONBridge, json.Marshaler keep encoding through
+// that method.
+func WithTypeDiscriminator(key string) EncoderOption {
+	return func(o *encoderOptions) {
+		o.typeDiscriminator = key
+	}
+}
+
+// WithNullLiteral replaces the "null" token emitted for nil values, for
+// example with "~". This departs from the TOON core profile, so documents
+// must be decoded with WithDecoderNullLiteral. Literals that are empty,
+// collide with other literals, or would require quoting are ignored. Strings
+// equal to the literal are quoted.
+func WithNullLiteral(literal string) EncoderOption {
+	return func(o *encoderOptions) {
+		if validNullLiteral(literal) {
+			o.nullLiteral = literal
+		}
+	}
+}
+
+// WithNaturalKeyOrder sorts map keys so that runs of digits compare by
+// numeric value, placing "item2" before "item10". Struct fields and Object
+// values keep their declared order regardless.
+func WithNaturalKeyOrder(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.naturalKeyOrder = enabled
+	}
+}
+
+// WithMapKeyComparator sorts map keys with compare, which returns a negative
+// number, zero or a positive number as a sorts before, equal to or after b.
+// Keys that compare equal fall back to lexicographic order so that output
+// stays deterministic. The comparator takes precedence over
+// WithNaturalKeyOrder; nil restores the default.
+func WithMapKeyComparator(compare func(a, b string) int) EncoderOption {
+	return func(o *encoderOptions) {
+		o.mapKeyCompare = compare
+	}
+}
+
+// WithEmitEmptyObject makes an empty root object encode as the single line
+// "{}" instead of an empty document, for consumers that cannot handle empty
+// input. The decoder reads a bare "{}" root as an empty object.
+func WithEmitEmptyObject(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.emitEmptyObject = enabled
+	}
+}
+
+// WithDelimiterEscaping writes the active delimiter inside inline and tabular
+// cells as a backslash escape, such as "a\|b", instead of quoting the whole
+// cell, when the delimiter is the only reason the cell needs quoting. Decode
+// such documents with WithDecoderDelimiterEscaping.
+func WithDelimiterEscaping(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.escapeDelimiters = enabled
+	}
+}
+
+// WithLossless makes Marshal fail instead of silently changing a value it
+// cannot write back exactly. The coercions it turns into errors are: NaN and
+// infinite floats or json.Number values written as null, negative zero
+// written as 0, json.Number literals rewritten in canonical form (such as 1.0
+// as 1 or 1e3 as 1000), and json.Number values that are not numbers written as
+// strings. Integers beyond ±(2^53-1), which are written as strings that
+// Unmarshal reads back into integer fields, are still allowed.
+func WithLossless(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.lossless = enabled
+	}
+}
+
+// WithDeterministic pins every setting that could make the output depend on
+// how a value was built or on configured callbacks, for content-addressed
+// artifacts. The keys of maps, structs and Object values are sorted by byte
+// order, times are written in UTC as RFC 3339 with nanoseconds, and
+// WithTimeFormatter, WithTimeLocation, WithMapKeyComparator and
+// WithNaturalKeyOrder are ignored. Numbers are always written as the shortest
+// decimal that round-trips, without exponents. The remaining options, such as
+// indentation and delimiters, still apply, so equal values encoded with
+// equal options produce identical bytes.
+func WithDeterministic(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.deterministic = enabled
+	}
+}
+
+// pinned returns o with the settings overridden by WithDeterministic.
+func (o encoderOptions) pinned() encoderOptions {
+	o.timeFormatter = nil
+	o.timeLocation = nil
+	o.mapKeyCompare = nil
+	o.naturalKeyOrder = false
+	return o
+}
+
+// WithNumericBooleans writes true and false as the unquoted numbers 1 and 0.
+// Decode reads them back as numbers; Unmarshal restores booleans in bool
+// fields when decoding with WithBoolLiterals([]string{"1"}, []string{"0"}).
+func WithNumericBooleans(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.numericBools = enabled
+	}
+}
+
+// WithSchemaHeader prepends text to the document as comment lines, each
+// starting with "# ", for example to describe the schema to an LLM reading
+// the document. TOON has no comments, so decode such documents with
+// WithSkipSchemaHeader.
+func WithSchemaHeader(text string) EncoderOption {
+	return func(o *encoderOptions) {
+		o.schemaHeader = text
+	}
+}
+
+// WithMaxInlineWidth limits the length, in characters and including
+// indentation, of inline primitive arrays and tabular rows. A primitive array
+// whose line would be longer is written in list form with one "- " item per
+// value; a tabular array with any longer row is written as a list of objects.
+// The two decisions are made independently per array. A value of zero or less
+// disables the limit.
+func WithMaxInlineWidth(n int) EncoderOption {
+	return func(o *encoderOptions) {
+		o.maxInlineWidth = n
+	}
+}
+
+// WithAutoDelimiter chooses the delimiter of each inline and tabular array
+// among comma, tab and pipe, picking the one contained in the fewest string
+// cells so that the fewest cells need quoting. Ties keep the delimiter set by
+// WithArrayDelimiter.
+func WithAutoDelimiter(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.autoDelimiter = enabled
+	}
+}
+
+// WithTabularThreshold writes an array of objects with primitive values in
+// tabular form when at least ratio of its objects carry every field found
+// across the array, rather than only when all of them share the same fields.
+// The header lists the fields in first-seen order, and a field that an object
+// lacks is written as a null cell, so it decodes as null rather than absent.
+// A ratio of zero or less keeps the default of exact uniformity.
+func WithTabularThreshold(ratio float64) EncoderOption {
+	return func(o *encoderOptions) {
+		o.tabularThreshold = ratio
+	}
+}
+
 // DecoderOption mutates decoder behaviour.
 type DecoderOption func(*decoderOptions)
 
 type decoderOptions struct {
-	indentSize    int
-	strict        bool
-	documentDelim Delimiter
+	indentSize        int
+	strict            bool
+	documentDelim     Delimiter
+	jsonBridge        bool
+	errorOnEmpty      bool
+	maxInputBytes     int
+	maxLineBytes      int
+	maxElements       int
+	expandDottedKeys  bool
+	discriminatorKey  string
+	nullLiteral       string
+	tabularFillValue  any
+	allowNonFinite    bool
+	caseInsensitive   bool
+	extendedNumbers   bool
+	useNumber         bool
+	strictIntegers    bool
+	delimiterFallback bool
+	rootKey           string
+	orderedObjects    bool
+	skipSchemaHeader  bool
+	autoIndent        bool
+	tabIndent         bool
+	escapedDelimiters bool
+	forbiddenKeys     map[string]struct{}
+	boolLiterals      map[string]bool
+
+	unknownFieldHandler   func(path, key string, value any)
+	disallowUnknownFields bool
+
+	// presentFields collects the paths of assigned struct fields for
+	// DecodeFields. No option sets it.
+	presentFields *[]string
+	// lint collects recoverable problems for Lint. No option sets it.
+	lint *lintState
 }
 
 func defaultDecoderOptions() decoderOptions {
 	return decoderOptions{
-		indentSize:    2,
-		strict:        true,
-		documentDelim: DelimiterComma,
+		indentSize:       2,
+		strict:           true,
+		documentDelim:    DelimiterComma,
+		discriminatorKey: defaultDiscriminatorKey,
 	}
 }
 
+func (o decoderOptions) tracksPaths() bool {
+	return o.unknownFieldHandler != nil || o.presentFields != nil
+}
+
 // WithStrictMode toggles the strict-mode diagnostics.
 func WithStrictMode(strict bool) DecoderOption {
 	return func(o *decoderOptions) {
@@ -143,6 +462,26 @@ func WithDecoderIndent(spaces int) DecoderOption {
 	}
 }
 
+// WithAutoIndent makes the decoder take the indentation step from the first
+// indented line of each document instead of from WithDecoderIndent, so
+// documents written with any consistent step decode without configuration.
+// Strict mode still requires every line to be a multiple of that step.
+func WithAutoIndent(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.autoIndent = enabled
+	}
+}
+
+// WithTabIndentation makes the decoder treat each leading tab as one
+// indentation level instead of rejecting tabs. Strict mode rejects lines that
+// mix tabs and spaces in their indentation; permissive mode counts the spaces
+// against the indentation step as usual.
+func WithTabIndentation(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.tabIndent = enabled
+	}
+}
+
 // WithDecoderDocumentDelimiter configures the delimiter that influences
 // delimiter-aware string parsing when no array header is active.
 func WithDecoderDocumentDelimiter(delimiter Delimiter) DecoderOption {
@@ -152,3 +491,273 @@ func WithDecoderDocumentDelimiter(delimiter Delimiter) DecoderOption {
 		}
 	}
 }
+
+// WithDecoderJSONBridge makes Unmarshal populate destinations implementing
+// json.Unmarshaler by re-encoding the decoded subtree as JSON and calling
+// UnmarshalJSON.
+func WithDecoderJSONBridge(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.jsonBridge = enabled
+	}
+}
+
+// WithErrorOnEmpty makes Decode and Unmarshal return ErrEmptyDocument for input
+// that is empty or whitespace-only instead of an empty object.
+func WithErrorOnEmpty(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.errorOnEmpty = enabled
+	}
+}
+
+// WithMaxInputBytes rejects documents larger than n bytes before parsing
+// begins. ReaderDecoder and GzipDecoder apply the limit to each document of
+// the stream, measured after decompression, and stop reading a document as
+// soon as it grows past n bytes. A value of zero or less disables the limit.
+func WithMaxInputBytes(n int) DecoderOption {
+	return func(o *decoderOptions) {
+		o.maxInputBytes = n
+	}
+}
+
+// WithMaxLineBytes rejects documents containing a line longer than n bytes,
+// excluding its line terminator, so a single pathological line is reported
+// with its line number. A value of zero or less disables the limit.
+func WithMaxLineBytes(n int) DecoderOption {
+	return func(o *decoderOptions) {
+		o.maxLineBytes = n
+	}
+}
+
+// WithMaxElements caps the total number of array elements, including tabular
+// rows, produced while decoding a document. A value of zero or less disables
+// the limit.
+func WithMaxElements(n int) DecoderOption {
+	return func(o *decoderOptions) {
+		o.maxElements = n
+	}
+}
+
+// WithDottedKeyExpansion expands unquoted dotted keys such as "a.b.c: 1" into
+// nested objects. Quoted keys stay literal. When an expanded path meets an
+// object already decoded at the same key, the two are merged; any other
+// collision is an error in strict mode, and the later value wins otherwise.
+func WithDottedKeyExpansion(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.expandDottedKeys = enabled
+	}
+}
+
+// WithDecoderTypeDiscriminator sets the field consulted when decoding an
+// object into an interface-typed destination. The field's value selects a
+// type registered with RegisterType. The default key is "_type".
+func WithDecoderTypeDiscriminator(key string) DecoderOption {
+	return func(o *decoderOptions) {
+		if key != "" {
+			o.discriminatorKey = key
+		}
+	}
+}
+
+// WithDecoderNullLiteral makes the decoder read the bare token literal as null
+// in addition to "null". Invalid literals are ignored, as with
+// WithNullLiteral.
+func WithDecoderNullLiteral(literal string) DecoderOption {
+	return func(o *decoderOptions) {
+		if validNullLiteral(literal) {
+			o.nullLiteral = literal
+		}
+	}
+}
+
+// WithDecoderTabularFillValue makes the decoder read a tabular row whose cells
+// all equal value, the placeholder given to WithTabularFillValue, as a null
+// element. Numbers are matched by their canonical form. Nil disables the
+// mapping.
+func WithDecoderTabularFillValue(value any) DecoderOption {
+	return func(o *decoderOptions) {
+		o.tabularFillValue = value
+	}
+}
+
+// WithCaseInsensitiveLiterals makes a permissive decoder read true, false and
+// null in any letter case, such as True, FALSE or Null, for ingesting
+// documents from producers that capitalize them. Strict mode ignores the
+// option and matches only the lowercase literals the specification defines.
+// Marshal does not quote strings such as "True", so enabling the option on
+// documents produced by this package can turn such strings into booleans.
+func WithCaseInsensitiveLiterals(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.caseInsensitive = enabled
+	}
+}
+
+// WithAllowNonFinite makes the decoder read the unquoted tokens NaN, Infinity
+// and -Infinity as the corresponding float64 values instead of strings, for
+// ingesting documents from producers that emit them. The option is
+// deliberately asymmetric: Marshal writes non-finite floats as null, and does
+// not quote a string such as "NaN", so enabling it on documents produced by
+// this package can turn such strings into numbers.
+func WithAllowNonFinite(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.allowNonFinite = enabled
+	}
+}
+
+// WithExtendedNumbers makes the decoder read unquoted integers with a 0x, 0o or
+// 0b prefix, such as 0xFF or -0b1010, as numbers instead of strings. The
+// encoder never writes these forms, and it leaves strings such as "0xFF"
+// unquoted, so enable the option only for producers that mean them as
+// numbers.
+func WithExtendedNumbers(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.extendedNumbers = enabled
+	}
+}
+
+// WithUseNumber makes Decode return numbers as json.Number values holding
+// their source text instead of float64, so literals such as 10.50 or
+// 12345678901234567890 keep their exact form. Marshal writes such values back
+// with all their digits, in canonical form. Unmarshal is unaffected.
+func WithUseNumber(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.useNumber = enabled
+	}
+}
+
+// WithStrictIntegerSources makes Unmarshal reject numbers written with a
+// fraction or exponent, such as 42.0 or 4.2e1, when the destination is an
+// integer, instead of accepting any integral value. This surfaces fields
+// whose producers have started emitting fractional numbers.
+func WithStrictIntegerSources(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.strictIntegers = enabled
+	}
+}
+
+// WithDelimiterFallback salvages documents whose array headers declare a tab
+// or pipe delimiter while the values are separated by commas. In permissive
+// mode, an inline array or tabular row that does not split into the declared
+// number of values with the declared delimiter is split by commas when that
+// yields the declared number instead. Strict mode ignores the option and
+// reports the mismatch.
+func WithDelimiterFallback(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.delimiterFallback = enabled
+	}
+}
+
+// WithRootKey makes Unmarshal decode the value stored under key in a root
+// object, instead of the whole document, into its target. A document of the
+// form "items[2]: ..." can then fill a []Item without a wrapper struct. The
+// other keys are ignored, and a missing key or a root that is not an object
+// is an error. Decode is unaffected.
+func WithRootKey(key string) DecoderOption {
+	return func(o *decoderOptions) {
+		o.rootKey = key
+	}
+}
+
+// WithOrderedObjects makes Decode return objects as Object values, which keep
+// the document's key order, instead of as map[string]any. Re-encoding such a
+// value reproduces the original key order.
+func WithOrderedObjects(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.orderedObjects = enabled
+	}
+}
+
+// WithSkipSchemaHeader makes the decoder ignore the lines starting with "#"
+// that precede the document, such as those written by WithSchemaHeader. Only
+// leading lines are skipped; a "#" line after the first content line is
+// parsed as usual.
+func WithSkipSchemaHeader(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.skipSchemaHeader = enabled
+	}
+}
+
+// WithDecoderDelimiterEscaping makes the decoder read a backslash followed by
+// the active delimiter, outside quotes in inline and tabular cells, as a
+// literal delimiter, matching documents written with WithDelimiterEscaping.
+func WithDecoderDelimiterEscaping(enabled bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.escapedDelimiters = enabled
+	}
+}
+
+// WithBoolLiterals makes the decoder read the unquoted tokens in trueSet as
+// true and those in falseSet as false, for producers that write yes/no or
+// on/off. Tokens listed in both sets, the keywords true, false and null, and
+// tokens starting with a quote are ignored, and quoted strings are never
+// converted. Tokens that look numeric, such as 1 and 0, still decode as
+// numbers; Unmarshal converts them only when the destination is a bool. Each
+// call replaces the previous sets.
+func WithBoolLiterals(trueSet, falseSet []string) DecoderOption {
+	return func(o *decoderOptions) {
+		o.boolLiterals = boolLiteralSet(trueSet, falseSet)
+	}
+}
+
+func boolLiteralSet(trueSet, falseSet []string) map[string]bool {
+	literals := make(map[string]bool, len(trueSet)+len(falseSet))
+	for _, token := range trueSet {
+		literals[token] = true
+	}
+	var conflicts []string
+	for _, token := range falseSet {
+		if literals[token] {
+			conflicts = append(conflicts, token)
+			continue
+		}
+		literals[token] = false
+	}
+	for _, token := range append(conflicts, "", "true", "false", "null") {
+		delete(literals, token)
+	}
+	for token := range literals {
+		if token[0] == '"' {
+			delete(literals, token)
+		}
+	}
+	if len(literals) == 0 {
+		return nil
+	}
+	return literals
+}
+
+// WithForbiddenKeys makes decoding fail when any of keys appears as an object
+// key or tabular field name anywhere in the document, whatever the
+// destination type, as a guard for untrusted input. With
+// WithDottedKeyExpansion each segment of a dotted key is checked as well.
+// Each call replaces the previous set.
+func WithForbiddenKeys(keys ...string) DecoderOption {
+	return func(o *decoderOptions) {
+		o.forbiddenKeys = nil
+		if len(keys) == 0 {
+			return
+		}
+		o.forbiddenKeys = make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			o.forbiddenKeys[key] = struct{}{}
+		}
+	}
+}
+
+// WithUnknownFieldHandler registers fn to be called for every object key that
+// does not match a field of the destination struct during Unmarshal. path
+// locates the containing object, using dots for keys and [i] for indices, and
+// is empty at the root. The handler runs before WithDisallowUnknownFields
+// reports its error.
+func WithUnknownFieldHandler(fn func(path, key string, value any)) DecoderOption {
+	return func(o *decoderOptions) {
+		o.unknownFieldHandler = fn
+	}
+}
+
+// WithDisallowUnknownFields makes Unmarshal fail when an object contains a key
+// that does not match a field of the destination struct.
+func WithDisallowUnknownFields(disallow bool) DecoderOption {
+	return func(o *decoderOptions) {
+		o.disallowUnknownFields = disallow
+	}
+}
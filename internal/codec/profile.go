@@ -0,0 +1,88 @@
+package codec
+
+// Profile names a bundle of encoder and decoder options for a common use case.
+type Profile int
+
+const (
+	// ProfileStrict emits length markers on every array header. The decoder
+	// side decodes in strict mode and rejects empty documents, unknown struct
+	// fields and fractional numbers for integer fields.
+	ProfileStrict Profile = iota + 1
+	// ProfileCompact minimizes output size: no length markers, the delimiter
+	// needing the fewest quotes for each array, and tabular rows for arrays in
+	// which at least half of the objects carry every field. A field that such
+	// an object lacks is written as null, which Unmarshal only accepts into
+	// pointer and interface fields. Otherwise its output reads back with the
+	// default decoder options, which is what the decoder side applies.
+	ProfileCompact
+	// ProfileVerbose favours readability: length markers and four-space
+	// indentation.
+	ProfileVerbose
+)
+
+// compactTabularThreshold is the WithTabularThreshold ratio of ProfileCompact.
+const compactTabularThreshold = 0.5
+
+func (p Profile) String() string {
+	switch p {
+	case ProfileStrict:
+		return "strict"
+	case ProfileCompact:
+		return "compact"
+	case ProfileVerbose:
+		return "verbose"
+	default:
+		return "profile(unknown)"
+	}
+}
+
+// WithProfile applies the encoder options bundled by profile. Options passed
+// after it override the profile's choices. Unknown profiles are ignored.
+func WithProfile(profile Profile) EncoderOption {
+	var opts []EncoderOption
+	switch profile {
+	case ProfileStrict:
+		opts = []EncoderOption{WithLengthMarkers(true)}
+	case ProfileCompact:
+		opts = []EncoderOption{
+			WithLengthMarkers(false),
+			WithAutoDelimiter(true),
+			WithTabularThreshold(compactTabularThreshold),
+		}
+	case ProfileVerbose:
+		opts = []EncoderOption{
+			WithLengthMarkers(true),
+			WithIndent(4),
+		}
+	}
+	return func(o *encoderOptions) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
+
+// WithDecoderProfile applies the decoder options bundled by profile, matching
+// documents produced with WithProfile. Options passed after it override the
+// profile's choices. Unknown profiles are ignored.
+func WithDecoderProfile(profile Profile) DecoderOption {
+	var opts []DecoderOption
+	switch profile {
+	case ProfileStrict:
+		opts = []DecoderOption{
+			WithStrictMode(true),
+			WithErrorOnEmpty(true),
+			WithDisallowUnknownFields(true),
+			WithStrictIntegerSources(true),
+		}
+	case ProfileCompact:
+		// Compact output reads back with the default options.
+	case ProfileVerbose:
+		opts = []DecoderOption{WithDecoderIndent(4)}
+	}
+	return func(o *decoderOptions) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// ReaderDecoder reads a stream of TOON documents from an io.Reader, such as
+// the output of WriterEncoder, whose documents are separated by lines holding
+// only "---". Line numbers in errors count from the start of each document.
+type ReaderDecoder struct {
+	r    *bufio.Reader
+	dec  *Decoder
+	opts []DecoderOption
+	buf  []byte
+	// pending is set after a separator, which promises another document
+	// even when nothing follows it.
+	pending bool
+}
+
+// NewReaderDecoder returns a decoder that reads documents from r using the
+// supplied options. WithMaxInputBytes applies to each document and stops
+// reading one as soon as it grows past the limit.
+func NewReaderDecoder(r io.Reader, opts ...DecoderOption) *ReaderDecoder {
+	return &ReaderDecoder{r: bufio.NewReader(r), dec: NewDecoder(opts...), opts: opts}
+}
+
+// Decode reads and decodes the next document. It returns io.EOF once the
+// stream holds no more documents.
+func (d *ReaderDecoder) Decode() (any, error) {
+	data, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	return d.dec.Decode(data)
+}
+
+// Unmarshal reads the next document and decodes it into v, as Unmarshal does.
+// It returns io.EOF once the stream holds no more documents.
+func (d *ReaderDecoder) Unmarshal(v any) error {
+	data, err := d.next()
+	if err != nil {
+		return err
+	}
+	return Unmarshal(data, v, d.opts...)
+}
+
+// next returns the text of the next document, without its separator. Lines
+// are read in buffer-sized chunks, so a document is abandoned as soon as it
+// passes WithMaxInputBytes, even within a single long line.
+func (d *ReaderDecoder) next() ([]byte, error) {
+	promised := d.pending
+	d.pending = false
+	d.buf = d.buf[:0]
+	read := false
+	lineStart := 0
+	for {
+		chunk, err := d.r.ReadSlice('\n')
+		if len(chunk) > 0 {
+			read = true
+			d.buf = append(d.buf, chunk...)
+		}
+		if err != bufio.ErrBufferFull {
+			if string(bytes.TrimRight(d.buf[lineStart:], "\r\n")) == documentSeparator {
+				d.pending = true
+				return d.buf[:lineStart], nil
+			}
+			lineStart = len(d.buf)
+		}
+		if limit := d.dec.cfg.maxInputBytes; limit > 0 && len(d.buf) > limit {
+			return nil, errInputTooLarge(limit)
+		}
+		switch err {
+		case nil, bufio.ErrBufferFull:
+		case io.EOF:
+			if !read && !promised {
+				return nil, io.EOF
+			}
+			return d.buf, nil
+		default:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package codec
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultDiscriminatorKey names the field that carries a registered type name.
+const defaultDiscriminatorKey = "_type"
+
+var registry = struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}{
+	types: make(map[string]reflect.Type),
+	names: make(map[reflect.Type]string),
+}
+
+// registeredCount mirrors len(registry.names), so that encoding skips the
+// lock entirely while no type has been registered.
+var registeredCount atomic.Int64
+
+// RegisterType associates name with the dynamic type of value so that objects
+// carrying the discriminator field can be decoded into interface-typed
+// destinations, including slice elements and map values such as
+// map[string]Shape. Registering a pointer value instantiates pointers. It
+// panics if name is empty or already bound to a different type, or if the
+// type is already registered under a different name.
+func RegisterType(name string, value any) {
+	if name == "" {
+		panic("toon: RegisterType with empty name")
+	}
+	if value == nil {
+		panic("toon: RegisterType with nil value")
+	}
+	typ := reflect.TypeOf(value)
+	registry.Lock()
+	defer registry.Unlock()
+	if existing, ok := registry.types[name]; ok && existing != typ {
+		panic(fmt.Sprintf("toon: name %q already registered for %s", name, existing))
+	}
+	if existing, ok := registry.names[typ]; ok && existing != name {
+		panic(fmt.Sprintf("toon: type %s already registered as %q", typ, existing))
+	}
+	registry.types[name] = typ
+	registry.names[typ] = name
+	registeredCount.Store(int64(len(registry.names)))
+}
+
+func registeredType(name string) (reflect.Type, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	typ, ok := registry.types[name]
+	return typ, ok
+}
+
+func registeredName(typ reflect.Type) (string, bool) {
+	if registeredCount.Load() == 0 {
+		return "", false
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	name, ok := registry.names[typ]
+	return name, ok
+}
+
+// normalizeRegistered normalizes a struct, or pointer to struct, whose type is
+// registered under name and prepends the discriminator field.
+func normalizeRegistered(val reflect.Value, name string, cfg encoderOptions) (normalizedValue, error) {
+	if val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	obj, err := normalizeStructValue(val, cfg)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]Field, 0, len(obj.Fields)+1)
+	fields = append(fields, Field{Key: cfg.typeDiscriminator, Value: name})
+	for _, field := range obj.Fields {
+		if field.Key != cfg.typeDiscriminator {
+			fields = append(fields, field)
+		}
+	}
+	return Object{Fields: fields}, nil
+}
+
+// assignRegistered decodes an object into a non-empty interface destination by
+// instantiating the registered type named by its discriminator field.
+func assignRegistered(dst reflect.Value, src any, cfg decoderOptions, path string) error {
+	obj, ok := asObject(src)
+	if !ok {
+		plain := reflect.ValueOf(plainValue(src))
+		if plain.Type().AssignableTo(dst.Type()) {
+			dst.Set(plain)
+			return nil
+		}
+		return fmt.Errorf("toon: cannot assign %T to %s", plainValue(src), dst.Type())
+	}
+	key := cfg.discriminatorKey
+	name, ok := objField(obj, key).(string)
+	if !ok {
+		return fmt.Errorf("toon: object for %s has no %q discriminator", dst.Type(), key)
+	}
+	typ, ok := registeredType(name)
+	if !ok {
+		return fmt.Errorf("toon: type %q is not registered", name)
+	}
+	rest := make([]Field, 0, len(obj.Fields))
+	for _, field := range obj.Fields {
+		if field.Key != key {
+			rest = append(rest, field)
+		}
+	}
+	target := reflect.New(typ)
+	if err := assignValue(target.Elem(), Object{Fields: rest}, cfg, path); err != nil {
+		return err
+	}
+	switch {
+	case typ.AssignableTo(dst.Type()):
+		dst.Set(target.Elem())
+	case target.Type().AssignableTo(dst.Type()):
+		dst.Set(target)
+	default:
+		return fmt.Errorf("toon: registered type %s does not implement %s", typ, dst.Type())
+	}
+	return nil
+}
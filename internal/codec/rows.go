@@ -0,0 +1,78 @@
+package codec
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DecodeRows decodes a document whose root is an array of objects, such as a
+// tabular array, and returns its rows as maps. It fails when the root is not
+// an array or any element is not an object. WithOrderedObjects is ignored, so
+// rows and the objects inside them are always maps.
+func DecodeRows(data []byte, opts ...DecoderOption) ([]map[string]any, error) {
+	value, err := decodeUnordered(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	return rowsOf(value, "root")
+}
+
+// DecodeRowsAt is like DecodeRows for the array stored under key in a
+// document whose root is an object.
+func DecodeRowsAt(data []byte, key string, opts ...DecoderOption) ([]map[string]any, error) {
+	value, err := decodeUnordered(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("toon: root is %s, not an object", kindOf(value))
+	}
+	field, ok := root[key]
+	if !ok {
+		return nil, fmt.Errorf("toon: key %q not found", key)
+	}
+	return rowsOf(field, fmt.Sprintf("%q", key))
+}
+
+// decodeUnordered decodes data with opts, overriding WithOrderedObjects so
+// that objects come back as maps.
+func decodeUnordered(data []byte, opts []DecoderOption) (any, error) {
+	return Decode(data, append(slices.Clip(opts), WithOrderedObjects(false))...)
+}
+
+// rowsOf converts an array of objects into rows; what names the array in
+// errors.
+func rowsOf(value any, what string) ([]map[string]any, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("toon: %s is %s, not an array", what, kindOf(value))
+	}
+	rows := make([]map[string]any, len(items))
+	for i, item := range items {
+		row, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("toon: %s[%d] is %s, not an object", what, i, kindOf(item))
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// kindOf names the data-model type of a decoded value for error messages.
+func kindOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case map[string]any, Object:
+		return "an object"
+	case []any:
+		return "an array"
+	default:
+		return "a number"
+	}
+}
@@ -0,0 +1,99 @@
+package codec
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var boolType = reflect.TypeFor[bool]()
+
+// seqArity reports whether t has the shape of iter.Seq (1) or iter.Seq2 (2):
+// a function taking a yield function with that many parameters that returns
+// bool. Defined types such as iter.Seq[int] match by shape. It returns 0 for
+// any other type.
+func seqArity(t reflect.Type) int {
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		return 0
+	}
+	yield := t.In(0)
+	if yield.Kind() != reflect.Func || yield.NumOut() != 1 || yield.Out(0) != boolType || yield.IsVariadic() {
+		return 0
+	}
+	if n := yield.NumIn(); n == 1 || n == 2 {
+		return n
+	}
+	return 0
+}
+
+// normalizeSeq consumes an iter.Seq into an array, or an iter.Seq2 with
+// string keys into an object whose fields follow the order of iteration. The
+// whole sequence is read before anything is encoded, since array headers
+// declare their length up front. A key that an iter.Seq2 yields twice is an
+// error, as an object cannot hold it twice.
+func normalizeSeq(val reflect.Value, arity int, cfg encoderOptions) (normalizedValue, error) {
+	yieldType := val.Type().In(0)
+	if arity == 2 && yieldType.In(0).Kind() != reflect.String {
+		return nil, &UnsupportedTypeError{Type: val.Type()}
+	}
+	var (
+		items  []normalizedValue
+		fields []Field
+		seen   map[string]struct{}
+		err    error
+	)
+	if arity == 2 {
+		seen = make(map[string]struct{})
+	}
+	stop := []reflect.Value{reflect.ValueOf(false)}
+	next := []reflect.Value{reflect.ValueOf(true)}
+	yield := reflect.MakeFunc(yieldType, func(args []reflect.Value) []reflect.Value {
+		// A sequence that ignores a false return keeps calling yield; the
+		// first error stands and later values are dropped.
+		if err != nil {
+			return stop
+		}
+		itemCfg, keep := cfg, true
+		if arity == 2 {
+			key := args[0].String()
+			if _, dup := seen[key]; dup {
+				err = fmt.Errorf("toon: duplicate key %q in iter.Seq2", key)
+				return stop
+			}
+			seen[key] = struct{}{}
+			itemCfg, keep = cfg.enterField(key)
+		} else {
+			itemCfg = cfg.enterIndex(len(items))
+		}
+		if !keep {
+			return next
+		}
+		mark := cfg.prunedMark()
+		item, itemErr := normalize(args[arity-1].Interface(), itemCfg)
+		if itemErr != nil {
+			err = itemErr
+			if arity == 2 {
+				err = fmt.Errorf("toon: %s: %w", args[0].String(), itemErr)
+			}
+			return stop
+		}
+		if arity == 2 {
+			if !cfg.emptiedSince(mark, item) {
+				fields = append(fields, Field{Key: args[0].String(), Value: item})
+			}
+		} else {
+			items = append(items, item)
+		}
+		return next
+	})
+	val.Call([]reflect.Value{yield})
+	if err != nil {
+		return nil, err
+	}
+	if arity == 2 {
+		return sortedFields(fields, cfg), nil
+	}
+	if items == nil {
+		items = []normalizedValue{}
+	}
+	return items, nil
+}
@@ -0,0 +1,82 @@
+package codec
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Set decodes data, stores value at path and returns the document re-encoded
+// with the default encoder options. Paths use the notation of Get. Objects
+// keep their key order, and a new key is appended to its object. Missing or
+// null values met by a key segment are replaced by new objects, so
+// intermediate objects are created as needed. An index equal to the array
+// length appends value; larger indices are an error, as is a segment meeting
+// a value of the wrong type. The empty path replaces the whole document.
+// Numbers elsewhere in the document keep their source text.
+func Set(data []byte, path string, value any, opts ...DecoderOption) ([]byte, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := NewDecoder(opts...).decode(data, true)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := setPath(decoded, segments, value, "")
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(updated)
+}
+
+// setPath returns node with value stored at segments; walked is the path of
+// node, for errors.
+func setPath(node any, segments []pathSegment, value any, walked string) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+	seg := segments[0]
+	if seg.key != "" {
+		obj, ok := node.(Object)
+		if !ok && node != nil {
+			return nil, fmt.Errorf("toon: %s is %s, not an object", pathName(walked), kindOf(node))
+		}
+		for i, field := range obj.Fields {
+			if field.Key == seg.key {
+				child, err := setPath(field.Value, segments[1:], value, diffKeyPath(walked, seg.key))
+				if err != nil {
+					return nil, err
+				}
+				obj.Fields[i].Value = child
+				return obj, nil
+			}
+		}
+		child, err := setPath(nil, segments[1:], value, diffKeyPath(walked, seg.key))
+		if err != nil {
+			return nil, err
+		}
+		obj.Fields = append(obj.Fields, Field{Key: seg.key, Value: child})
+		return obj, nil
+	}
+	items, ok := node.([]any)
+	if !ok {
+		return nil, fmt.Errorf("toon: %s is %s, not an array", pathName(walked), kindOf(node))
+	}
+	if seg.index > len(items) {
+		return nil, fmt.Errorf("toon: %s[%d] out of range for length %d", walked, seg.index, len(items))
+	}
+	itemPath := walked + "[" + strconv.Itoa(seg.index) + "]"
+	if seg.index == len(items) {
+		child, err := setPath(nil, segments[1:], value, itemPath)
+		if err != nil {
+			return nil, err
+		}
+		return append(items, child), nil
+	}
+	child, err := setPath(items[seg.index], segments[1:], value, itemPath)
+	if err != nil {
+		return nil, err
+	}
+	items[seg.index] = child
+	return items, nil
+}
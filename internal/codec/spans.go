@@ -0,0 +1,108 @@
+package codec
+
+import "strconv"
+
+// Span locates a decoded object or array in its source document. Lines are
+// 1-based and inclusive; EndLine is the last non-blank line of the node.
+// Obtain spans with Decoder.DecodeSpans.
+type Span struct {
+	StartLine int
+	EndLine   int
+}
+
+// DecodeSpans decodes data like Decode and also reports the source span of
+// every object and array, keyed by path. Paths use the notation of Diff:
+// dotted keys with [index] suffixes, and "" for the root. Keys that themselves
+// contain dots or brackets make paths ambiguous.
+func (d *Decoder) DecodeSpans(data []byte) (any, map[string]Span, error) {
+	if d.cfg.maxInputBytes > 0 && len(data) > d.cfg.maxInputBytes {
+		return nil, nil, errInputTooLarge(d.cfg.maxInputBytes)
+	}
+	p, err := newParser(string(data), d.cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.spans = map[string]Span{}
+	value, err := p.parseDocument()
+	if err != nil {
+		return nil, nil, err
+	}
+	if isContainer(value) {
+		if first, last := p.firstLine(0), p.lastLine(len(p.lines)); first > 0 {
+			p.spans[""] = Span{StartLine: first, EndLine: last}
+		}
+	}
+	return value, p.spans, nil
+}
+
+// spanMark remembers the enclosing path while a child value is parsed.
+type spanMark struct {
+	path  string
+	start int
+}
+
+// enterKey descends into the value of key, which starts on line start.
+func (p *parser) enterKey(key string, start int) spanMark {
+	if p.spans == nil {
+		return spanMark{}
+	}
+	mark := spanMark{path: p.path, start: start}
+	if p.path == "" {
+		p.path = key
+	} else {
+		p.path += "." + key
+	}
+	return mark
+}
+
+// enterIndex descends into the array element at index, which starts on the
+// next non-blank line.
+func (p *parser) enterIndex(index int) spanMark {
+	if p.spans == nil {
+		return spanMark{}
+	}
+	mark := spanMark{path: p.path, start: p.firstLine(p.pos)}
+	p.path += "[" + strconv.Itoa(index) + "]"
+	return mark
+}
+
+// leave records the span of value when it is an object or array and returns
+// to the enclosing path.
+func (p *parser) leave(mark spanMark, value any) {
+	if p.spans == nil {
+		return
+	}
+	if isContainer(value) {
+		p.spans[p.path] = Span{StartLine: mark.start, EndLine: p.lastLine(p.pos)}
+	}
+	p.path = mark.path
+}
+
+// firstLine returns the number of the first non-blank line at or after pos.
+func (p *parser) firstLine(pos int) int {
+	for ; pos < len(p.lines); pos++ {
+		if !p.lines[pos].blank {
+			return p.lines[pos].number
+		}
+	}
+	return 0
+}
+
+// lastLine returns the number of the last non-blank line before pos.
+func (p *parser) lastLine(pos int) int {
+	for pos--; pos >= 0; pos-- {
+		if !p.lines[pos].blank {
+			return p.lines[pos].number
+		}
+	}
+	return 0
+}
+
+func isContainer(v any) bool {
+	switch v.(type) {
+	case map[string]any, Object, []any:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,105 @@
+package codec
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	scannerType  = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	nullTimeType = reflect.TypeOf(sql.NullTime{})
+)
+
+func implementsScanner(dst reflect.Value) bool {
+	return dst.Kind() != reflect.Interface && dst.CanAddr() && dst.Addr().Type().Implements(scannerType)
+}
+
+// assignScanner hands src to the destination's Scan method. Numbers are passed
+// as int64 when they are integers that fit, and as float64 otherwise, matching
+// the values a database driver would supply; nil reaches Scan as well so that
+// types such as sql.NullString can record the null. sql.NullTime receives
+// strings parsed as RFC 3339 times, the form Marshal writes for time.Time.
+func assignScanner(dst reflect.Value, src any) error {
+	value := scanValue(src)
+	if str, ok := value.(string); ok && dst.Type() == nullTimeType {
+		var t time.Time
+		if err := t.UnmarshalText([]byte(str)); err != nil {
+			return fmt.Errorf("toon: %w", err)
+		}
+		value = t
+	}
+	s := dst.Addr().Interface().(sql.Scanner)
+	if err := s.Scan(value); err != nil {
+		return fmt.Errorf("toon: Scan: %w", err)
+	}
+	return nil
+}
+
+func scanValue(src any) any {
+	switch val := src.(type) {
+	case numberLiteral, float64:
+		if n, ok, err := integerValue(val, nil, false); err == nil && ok && n.IsInt64() {
+			return n.Int64()
+		}
+		num, _ := toFloat64(val)
+		return num
+	default:
+		return plainValue(src)
+	}
+}
+
+// normalizeValuer encodes v as the value returned by its Value method, so that
+// sql.NullString{Valid: false} becomes null. Valuers are consulted after
+// time.Time but before fmt.Stringer, the JSON bridge and reflection.
+func normalizeValuer(v driver.Valuer, cfg encoderOptions) (normalizedValue, error) {
+	value, err := v.Value()
+	if err != nil {
+		return nil, fmt.Errorf("toon: Value: %w", err)
+	}
+	if _, self := value.(driver.Valuer); self {
+		return nil, fmt.Errorf("toon: Value of %T returned a driver.Valuer", v)
+	}
+	return normalize(value, cfg)
+}
+
+// MarshalRows reads the remaining rows of a query result and encodes them as
+// a root tabular array whose fields are the result's column names. NULL
+// columns become null and []byte columns strings. Reading every row closes
+// rows, as with any loop over Next; the caller still closes rows when
+// MarshalRows fails. A result without rows encodes as an empty array.
+func MarshalRows(rows *sql.Rows, opts ...EncoderOption) ([]byte, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("toon: %w", err)
+	}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var records []any
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("toon: Scan: %w", err)
+		}
+		fields := make([]Field, len(columns))
+		for i, column := range columns {
+			value := values[i]
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			fields[i] = Field{Key: column, Value: value}
+		}
+		records = append(records, Object{Fields: fields})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("toon: %w", err)
+	}
+	if records == nil {
+		records = []any{}
+	}
+	return Marshal(records, opts...)
+}
@@ -0,0 +1,49 @@
+package codec
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Stats summarizes a rendered TOON document.
+type Stats struct {
+	// Bytes is the length of the document in bytes.
+	Bytes int
+	// Lines is the number of lines in the document.
+	Lines int
+	// TabularArrays counts the arrays emitted in tabular form.
+	TabularArrays int
+	// EstimatedTokens is the EstimateTokens approximation for the document.
+	EstimatedTokens int
+}
+
+// MarshalWithStats is equivalent to Marshal but also reports Stats for the
+// rendered document.
+func (e *Encoder) MarshalWithStats(v any) ([]byte, Stats, error) {
+	state, err := e.encode(v)
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	output := strings.Join(state.lines, "\n")
+	stats := Stats{
+		Bytes:           len(output),
+		Lines:           len(state.lines),
+		TabularArrays:   state.tabularArrays,
+		EstimatedTokens: EstimateTokens(output),
+	}
+	return []byte(output), stats, nil
+}
+
+// MarshalWithStats encodes v using a temporary encoder and reports Stats for
+// the rendered document.
+func MarshalWithStats(v any, opts ...EncoderOption) ([]byte, Stats, error) {
+	return NewEncoder(opts...).MarshalWithStats(v)
+}
+
+// EstimateTokens approximates the number of LLM tokens in s using the common
+// heuristic of four characters per token. It is intended for comparing
+// formats, not for exact budgeting against a specific tokenizer.
+func EstimateTokens(s string) int {
+	n := utf8.RuneCountInString(s)
+	return (n + 3) / 4
+}
@@ -1,7 +1,10 @@
 package codec
 
 import (
+	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -9,7 +12,21 @@ import (
 type structFieldMeta struct {
 	name      string
 	omitEmpty bool
+	omitZero  bool
 	index     []int
+	// precision is the number of decimals set by a prec=N tag option, or -1.
+	precision int
+	// method names the accessor set by a method=Name tag option, which the
+	// encoder calls instead of reading the field.
+	method string
+	// root marks the field tagged with the root option, which stands in for
+	// the whole struct.
+	root bool
+	// hex marks a byte slice or array encoded as a lowercase hex string.
+	hex bool
+	// promote marks an untagged embedded interface, whose dynamic value has
+	// its fields promoted into the parent when it is a struct.
+	promote bool
 }
 
 type structMeta struct {
@@ -17,6 +34,21 @@ type structMeta struct {
 	lookup map[string]structFieldMeta
 }
 
+// rootField returns the field tagged with the root option, if any. The option
+// is only valid on a struct's single encoded field.
+func (m structMeta) rootField(t reflect.Type) (structFieldMeta, bool, error) {
+	for _, field := range m.fields {
+		if !field.root {
+			continue
+		}
+		if len(m.fields) != 1 {
+			return field, true, fmt.Errorf("toon: %s: root field %s must be the only field", t, field.name)
+		}
+		return field, true, nil
+	}
+	return structFieldMeta{}, false, nil
+}
+
 var structCache sync.Map // map[reflect.Type]structMeta
 
 func cachedStructMeta(t reflect.Type) structMeta {
@@ -31,44 +63,114 @@ func cachedStructMeta(t reflect.Type) structMeta {
 func buildStructMeta(t reflect.Type) structMeta {
 	fields := make([]structFieldMeta, 0, t.NumField())
 	lookup := make(map[string]structFieldMeta, t.NumField())
+	aliases := map[string]structFieldMeta{}
 	for i := 0; i < t.NumField(); i++ {
 		sf := t.Field(i)
-		if !sf.IsExported() {
-			continue
-		}
 		tag := sf.Tag.Get("toon")
 		if tag == "-" {
 			continue
 		}
 		name, opts := parseStructTag(tag)
+		method := tagOption(opts, "method=")
+		// Unexported fields are only encoded through an accessor method, and
+		// never decoded since they cannot be set.
+		if !sf.IsExported() && method == "" {
+			continue
+		}
+		promote := sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Interface
 		if name == "" {
 			name = sf.Name
 		}
 		meta := structFieldMeta{
 			name:      name,
-			omitEmpty: opts["omitempty"],
+			omitEmpty: slices.Contains(opts, "omitempty"),
+			omitZero:  slices.Contains(opts, "omitzero"),
 			index:     sf.Index,
+			precision: tagPrecision(opts),
+			method:    method,
+			root:      slices.Contains(opts, "root"),
+			hex:       slices.Contains(opts, "hex"),
+			promote:   promote,
 		}
 		fields = append(fields, meta)
+		if !sf.IsExported() {
+			continue
+		}
 		lookup[name] = meta
+		for _, alias := range strings.Split(sf.Tag.Get("toonalias"), ",") {
+			if _, seen := aliases[alias]; alias != "" && !seen {
+				aliases[alias] = meta
+			}
+		}
+	}
+	// Aliases are only consulted when decoding, and never shadow a field's
+	// primary name.
+	for alias, meta := range aliases {
+		if _, taken := lookup[alias]; !taken {
+			lookup[alias] = meta
+		}
 	}
 	return structMeta{fields: fields, lookup: lookup}
 }
 
-func parseStructTag(tag string) (string, map[string]bool) {
-	options := map[string]bool{}
+// parseStructTag splits a toon tag into the field name and its options, kept
+// in the order written so that a repeated option resolves deterministically.
+func parseStructTag(tag string) (string, []string) {
 	if tag == "" {
-		return "", options
+		return "", nil
 	}
 	parts := strings.Split(tag, ",")
-	name := parts[0]
+	options := make([]string, 0, len(parts)-1)
 	for _, opt := range parts[1:] {
-		if opt == "" {
-			continue
+		if opt != "" {
+			options = append(options, opt)
+		}
+	}
+	return parts[0], options
+}
+
+// tagOption returns the value of the first tag option starting with prefix,
+// such as the N of prec=N, or "" when there is none.
+func tagOption(options []string, prefix string) string {
+	for _, opt := range options {
+		if value, ok := strings.CutPrefix(opt, prefix); ok {
+			return value
 		}
-		options[opt] = true
 	}
-	return name, options
+	return ""
+}
+
+// tagPrecision returns the N of a prec=N tag option, or -1 when the option is
+// absent or N is not a non-negative integer.
+func tagPrecision(options []string) int {
+	if n, err := strconv.Atoi(tagOption(options, "prec=")); err == nil && n >= 0 {
+		return n
+	}
+	return -1
+}
+
+// fieldValueByMethod calls the accessor named by field.method on v, which
+// must take no arguments and return a single value. Methods with pointer
+// receivers are called on a copy of v when v is not addressable.
+func fieldValueByMethod(v reflect.Value, field structFieldMeta) (reflect.Value, error) {
+	m := v.MethodByName(field.method)
+	if !m.IsValid() {
+		ptr := v
+		if v.CanAddr() {
+			ptr = v.Addr()
+		} else {
+			ptr = reflect.New(v.Type())
+			ptr.Elem().Set(v)
+		}
+		m = ptr.MethodByName(field.method)
+	}
+	if !m.IsValid() {
+		return reflect.Value{}, fmt.Errorf("toon: method %s not found on %s", field.method, v.Type())
+	}
+	if mt := m.Type(); mt.NumIn() != 0 || mt.NumOut() != 1 {
+		return reflect.Value{}, fmt.Errorf("toon: method %s on %s must take no arguments and return one value", field.method, v.Type())
+	}
+	return m.Call(nil)[0], nil
 }
 
 func fieldValueByIndex(v reflect.Value, index []int) reflect.Value {
@@ -84,6 +186,39 @@ func fieldValueByIndex(v reflect.Value, index []int) reflect.Value {
 	return v
 }
 
+type isZeroer interface {
+	IsZero() bool
+}
+
+var isZeroerType = reflect.TypeOf((*isZeroer)(nil)).Elem()
+
+// isZeroValue reports whether a field tagged omitzero should be dropped,
+// following encoding/json: a value whose type has an IsZero() bool method is
+// zero when the method says so, and any other value when it equals its type's
+// zero value. Unlike omitempty, empty but non-nil slices and maps, and
+// structs with an IsZero method reporting false, are kept.
+func isZeroValue(v reflect.Value) bool {
+	if v.Type().Implements(isZeroerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return true
+		}
+		if v.Kind() == reflect.Interface && v.IsNil() {
+			return true
+		}
+		return v.Interface().(isZeroer).IsZero()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(isZeroerType) {
+		return v.Addr().Interface().(isZeroer).IsZero()
+	}
+	return v.IsZero()
+}
+
+// isEmptyValue reports whether a field tagged omitempty should be dropped.
+// Strings, slices, maps and arrays are empty at length zero, so nil and empty
+// maps or slices are treated alike. Numbers and bools are empty at their zero
+// value, pointers when nil, and interfaces when nil or holding a nil pointer,
+// since both encode as null. Structs are empty when every field is the zero
+// value, which also covers a zero time.Time.
 func isEmptyValue(v reflect.Value) bool {
 	switch v.Kind() {
 	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
@@ -96,10 +231,16 @@ func isEmptyValue(v reflect.Value) bool {
 		return v.Uint() == 0
 	case reflect.Float32, reflect.Float64:
 		return v.Float() == 0
-	case reflect.Interface, reflect.Pointer:
+	case reflect.Pointer:
 		return v.IsNil()
+	case reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		elem := v.Elem()
+		return elem.Kind() == reflect.Pointer && elem.IsNil()
 	case reflect.Struct:
-		return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+		return v.IsZero()
 	}
 	return false
 }
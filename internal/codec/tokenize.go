@@ -0,0 +1,113 @@
+package codec
+
+import "strings"
+
+// LineKind classifies a line of a TOON document.
+type LineKind int
+
+const (
+	// LineBlank is an empty or whitespace-only line.
+	LineBlank LineKind = iota
+	// LineHeader declares an array, such as "items[2]:" or "[3]{a,b}:".
+	LineHeader
+	// LineKeyValue is a "key: value" pair or a "key:" object opener.
+	LineKeyValue
+	// LineListItem starts with the "- " list-item marker.
+	LineListItem
+	// LineValue is a bare value such as a tabular row or a root primitive.
+	LineValue
+)
+
+func (k LineKind) String() string {
+	switch k {
+	case LineBlank:
+		return "blank"
+	case LineHeader:
+		return "header"
+	case LineKeyValue:
+		return "key-value"
+	case LineListItem:
+		return "list-item"
+	case LineValue:
+		return "value"
+	default:
+		return "unknown"
+	}
+}
+
+// Line is a single classified line of a TOON document.
+type Line struct {
+	// Number is the 1-based line number.
+	Number int
+	// Indent is the indentation depth in levels, not spaces.
+	Indent int
+	// Kind classifies the line content.
+	Kind LineKind
+	// Content is the line with its indentation removed.
+	Content string
+}
+
+// Tokenize splits data into classified lines without building values. The
+// indentation rules of the supplied decoder options apply.
+func Tokenize(data []byte, opts ...DecoderOption) ([]Line, error) {
+	cfg := defaultDecoderOptions()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	p, err := newParser(string(data), cfg)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]Line, 0, len(p.lines))
+	for _, parsed := range p.lines {
+		kind, err := classifyLine(parsed)
+		if err != nil {
+			return nil, errorWrap(parsed.number, err)
+		}
+		lines = append(lines, Line{
+			Number:  parsed.number,
+			Indent:  parsed.indent,
+			Kind:    kind,
+			Content: parsed.content,
+		})
+	}
+	return lines, nil
+}
+
+// ParseHeader parses line as an array header and reports its key, declared
+// length, delimiter and tabular field names. Surrounding whitespace and a
+// leading "- " list-item marker are ignored, so the Content of a Line from
+// Tokenize can be passed directly. ok is false when line is not a header;
+// err is set when it looks like one but is malformed. The key is empty for
+// root and list-item arrays.
+func ParseHeader(line string) (key string, length int, delim Delimiter, fields []string, ok bool, err error) {
+	content := strings.TrimSpace(line)
+	if rest, found := strings.CutPrefix(content, "- "); found {
+		content = strings.TrimSpace(rest)
+	}
+	header, ok, err := tryParseHeader(content)
+	if err != nil || !ok {
+		return "", 0, 0, nil, false, err
+	}
+	return header.key, header.length, header.delimiter, header.fields, true, nil
+}
+
+func classifyLine(line parsedLine) (LineKind, error) {
+	if line.blank {
+		return LineBlank, nil
+	}
+	if line.content == "-" || strings.HasPrefix(line.content, "- ") {
+		return LineListItem, nil
+	}
+	_, isHeader, err := tryParseHeader(line.content)
+	if err != nil {
+		return 0, err
+	}
+	if isHeader {
+		return LineHeader, nil
+	}
+	if isKeyValue(line.content) {
+		return LineKeyValue, nil
+	}
+	return LineValue, nil
+}
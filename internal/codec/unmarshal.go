@@ -1,15 +1,24 @@
 package codec
 
 import (
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"math"
+	"math/big"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // Unmarshal decodes the TOON document in data into v, which must be a non-nil
 // pointer. Struct fields use `toon` struct tags for naming and omitempty
-// semantics, mirroring Marshal behaviour.
+// semantics, mirroring Marshal behaviour. A `toonalias` tag lists further
+// comma-separated keys accepted for a field, which Marshal ignores in favour
+// of the primary name. Object destinations receive fields in document order,
+// with nested objects also decoded as Object.
 func Unmarshal(data []byte, v any, opts ...DecoderOption) error {
 	if v == nil {
 		return errors.New("toon: Unmarshal nil target")
@@ -18,11 +27,50 @@ func Unmarshal(data []byte, v any, opts ...DecoderOption) error {
 	if rv.Kind() != reflect.Pointer || rv.IsNil() {
 		return errors.New("toon: Unmarshal target must be a non-nil pointer")
 	}
-	decoded, err := Decode(data, opts...)
+	dec := NewDecoder(opts...)
+	return dec.unmarshal(data, rv, dec.cfg)
+}
+
+// DecodeFields decodes data into v like Unmarshal and reports the paths of the
+// struct fields that were present in the document and assigned, in document
+// order. Paths use dots for keys and [i] for indices; a nested struct field is
+// listed before the fields inside it. Fields absent from the document keep
+// their previous values, which makes DecodeFields suitable for PATCH-style
+// merges.
+func (d *Decoder) DecodeFields(data []byte, v any) ([]string, error) {
+	if v == nil {
+		return nil, errors.New("toon: DecodeFields nil target")
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, errors.New("toon: DecodeFields target must be a non-nil pointer")
+	}
+	present := []string{}
+	cfg := d.cfg
+	cfg.presentFields = &present
+	if err := d.unmarshal(data, rv, cfg); err != nil {
+		return nil, err
+	}
+	return present, nil
+}
+
+func (d *Decoder) unmarshal(data []byte, rv reflect.Value, cfg decoderOptions) error {
+	decoded, err := d.decode(data, true)
 	if err != nil {
 		return err
 	}
-	return assignValue(rv.Elem(), decoded)
+	if cfg.rootKey != "" {
+		obj, ok := decoded.(Object)
+		if !ok {
+			return fmt.Errorf("toon: root is %s, not an object with key %q", kindOf(decoded), cfg.rootKey)
+		}
+		value, ok := obj.Get(cfg.rootKey)
+		if !ok {
+			return fmt.Errorf("toon: root key %q not found", cfg.rootKey)
+		}
+		return assignValue(rv.Elem(), value, cfg, keyPath(cfg, "", cfg.rootKey))
+	}
+	return assignValue(rv.Elem(), decoded, cfg, "")
 }
 
 // UnmarshalString decodes the TOON document in s into v.
@@ -30,10 +78,33 @@ func UnmarshalString(s string, v any, opts ...DecoderOption) error {
 	return Unmarshal([]byte(s), v, opts...)
 }
 
-func assignValue(dst reflect.Value, src any) error {
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func implementsTextUnmarshaler(dst reflect.Value) bool {
+	return dst.Kind() != reflect.Interface && dst.CanAddr() && dst.Addr().Type().Implements(textUnmarshalerType)
+}
+
+// assignValue stores the decoded value src into dst. Destinations implementing
+// encoding.TextUnmarshaler receive string values verbatim, taking precedence
+// over the opt-in JSON bridge. Destinations implementing sql.Scanner are
+// populated through Scan next, before the reflection-based conversions.
+func assignValue(dst reflect.Value, src any, cfg decoderOptions, path string) error {
 	if !dst.CanSet() {
 		return errors.New("toon: cannot set destination value")
 	}
+	if str, ok := src.(string); ok && implementsTextUnmarshaler(dst) {
+		u := dst.Addr().Interface().(encoding.TextUnmarshaler)
+		if err := u.UnmarshalText([]byte(str)); err != nil {
+			return fmt.Errorf("toon: UnmarshalText: %w", err)
+		}
+		return nil
+	}
+	if cfg.jsonBridge && src != nil && implementsJSONUnmarshaler(dst) {
+		return assignJSONUnmarshaler(dst, src)
+	}
+	if implementsScanner(dst) {
+		return assignScanner(dst, src)
+	}
 
 	switch dst.Kind() {
 	case reflect.Interface:
@@ -41,7 +112,10 @@ func assignValue(dst reflect.Value, src any) error {
 			dst.SetZero()
 			return nil
 		}
-		dst.Set(reflect.ValueOf(src))
+		if dst.Type().NumMethod() > 0 {
+			return assignRegistered(dst, src, cfg, path)
+		}
+		dst.Set(reflect.ValueOf(plainValue(src)))
 		return nil
 	case reflect.Pointer:
 		if src == nil {
@@ -51,20 +125,44 @@ func assignValue(dst reflect.Value, src any) error {
 		if dst.IsNil() {
 			dst.Set(reflect.New(dst.Type().Elem()))
 		}
-		return assignValue(dst.Elem(), src)
+		return assignValue(dst.Elem(), src, cfg, path)
 	case reflect.Struct:
-		obj, ok := src.(map[string]any)
+		if field, ok, err := cachedStructMeta(dst.Type()).rootField(dst.Type()); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			return assignValue(dst.FieldByIndex(field.index), src, cfg, path)
+		}
+		obj, ok := asObject(src)
 		if !ok {
-			return fmt.Errorf("toon: expected object for struct, got %T", src)
+			return fmt.Errorf("toon: expected object for struct, got %T", plainValue(src))
+		}
+		if dst.Type() == objectType {
+			dst.Set(reflect.ValueOf(exportValue(obj, exportOrdered)))
+			return nil
 		}
 		meta := cachedStructMeta(dst.Type())
-		for _, fieldMeta := range meta.fields {
-			value, exists := obj[fieldMeta.name]
+		for _, field := range obj.Fields {
+			fieldMeta, exists := meta.lookup[field.Key]
 			if !exists {
+				if cfg.unknownFieldHandler != nil {
+					cfg.unknownFieldHandler(path, field.Key, plainValue(field.Value))
+				}
+				if cfg.disallowUnknownFields {
+					return fmt.Errorf("toon: unknown field %q", field.Key)
+				}
 				continue
 			}
+			fieldPath := keyPath(cfg, path, field.Key)
+			if cfg.presentFields != nil {
+				*cfg.presentFields = append(*cfg.presentFields, fieldPath)
+			}
 			fieldValue := dst.FieldByIndex(fieldMeta.index)
-			if err := assignValue(fieldValue, value); err != nil {
+			assign := assignValue
+			if fieldMeta.hex {
+				assign = assignHex
+			}
+			if err := assign(fieldValue, field.Value, cfg, fieldPath); err != nil {
 				return fmt.Errorf("%s: %w", fieldMeta.name, err)
 			}
 		}
@@ -73,19 +171,24 @@ func assignValue(dst reflect.Value, src any) error {
 		if dst.Type().Key().Kind() != reflect.String {
 			return fmt.Errorf("toon: map key type must be string, got %s", dst.Type().Key())
 		}
-		obj, ok := src.(map[string]any)
+		obj, ok := asObject(src)
 		if !ok {
-			return fmt.Errorf("toon: expected object for map, got %T", src)
+			return fmt.Errorf("toon: expected object for map, got %T", plainValue(src))
 		}
 		if dst.IsNil() {
 			dst.Set(reflect.MakeMap(dst.Type()))
 		}
-		for key, value := range obj {
+		// Visit keys in sorted order so the reported error path is stable.
+		fields := slices.Clone(obj.Fields)
+		slices.SortStableFunc(fields, func(a, b Field) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+		for _, field := range fields {
 			elem := reflect.New(dst.Type().Elem()).Elem()
-			if err := assignValue(elem, value); err != nil {
-				return fmt.Errorf("%s: %w", key, err)
+			if err := assignValue(elem, field.Value, cfg, keyPath(cfg, path, field.Key)); err != nil {
+				return fmt.Errorf("%s: %w", field.Key, err)
 			}
-			dst.SetMapIndex(reflect.ValueOf(key), elem)
+			dst.SetMapIndex(reflect.ValueOf(field.Key), elem)
 		}
 		return nil
 	case reflect.Slice:
@@ -101,11 +204,11 @@ func assignValue(dst reflect.Value, src any) error {
 		}
 		arr, ok := src.([]any)
 		if !ok {
-			return fmt.Errorf("toon: expected array for slice, got %T", src)
+			return fmt.Errorf("toon: expected array for slice, got %T", plainValue(src))
 		}
 		slice := reflect.MakeSlice(dst.Type(), len(arr), len(arr))
 		for i, item := range arr {
-			if err := assignValue(slice.Index(i), item); err != nil {
+			if err := assignValue(slice.Index(i), item, cfg, indexPath(cfg, path, i)); err != nil {
 				return fmt.Errorf("index %d: %w", i, err)
 			}
 		}
@@ -114,13 +217,13 @@ func assignValue(dst reflect.Value, src any) error {
 	case reflect.Array:
 		arr, ok := src.([]any)
 		if !ok {
-			return fmt.Errorf("toon: expected array for fixed array, got %T", src)
+			return fmt.Errorf("toon: expected array for fixed array, got %T", plainValue(src))
 		}
 		if len(arr) != dst.Len() {
 			return fmt.Errorf("toon: array length mismatch: expected %d, got %d", dst.Len(), len(arr))
 		}
 		for i := 0; i < dst.Len(); i++ {
-			if err := assignValue(dst.Index(i), arr[i]); err != nil {
+			if err := assignValue(dst.Index(i), arr[i], cfg, indexPath(cfg, path, i)); err != nil {
 				return fmt.Errorf("index %d: %w", i, err)
 			}
 		}
@@ -131,56 +234,117 @@ func assignValue(dst reflect.Value, src any) error {
 			dst.SetString(val)
 			return nil
 		default:
-			return fmt.Errorf("toon: cannot assign %T to string", src)
+			return fmt.Errorf("toon: cannot assign %T to string", plainValue(src))
 		}
 	case reflect.Bool:
 		if b, ok := src.(bool); ok {
 			dst.SetBool(b)
 			return nil
 		}
-		return fmt.Errorf("toon: cannot assign %T to bool", src)
+		if num, ok := src.(numberLiteral); ok {
+			if b, ok := cfg.boolLiterals[num.text]; ok {
+				dst.SetBool(b)
+				return nil
+			}
+		}
+		return fmt.Errorf("toon: cannot assign %T to bool", plainValue(src))
 	case reflect.Float32, reflect.Float64:
+		// Parsing float32 destinations straight from the literal avoids
+		// rounding twice, first to float64 and then to float32.
+		if num, ok := src.(numberLiteral); ok && dst.Kind() == reflect.Float32 {
+			if f, err := strconv.ParseFloat(num.text, 32); err == nil {
+				dst.SetFloat(f)
+				return nil
+			}
+		}
 		if num, ok := toFloat64(src); ok {
 			dst.SetFloat(num)
 			return nil
 		}
-		return fmt.Errorf("toon: cannot assign %T to float", src)
+		return fmt.Errorf("toon: cannot assign %T to float", plainValue(src))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if num, ok := toFloat64(src); ok {
-			if math.Trunc(num) != num {
-				return fmt.Errorf("toon: cannot assign non-integer %v to %s", num, dst.Type())
-			}
-			intVal := int64(num)
-			if dst.OverflowInt(intVal) {
-				return fmt.Errorf("toon: integer %v overflows %s", num, dst.Type())
-			}
-			dst.SetInt(intVal)
-			return nil
+		n, ok, err := integerValue(src, dst.Type(), cfg.strictIntegers)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("toon: cannot assign %T to int", plainValue(src))
+		}
+		if !n.IsInt64() || dst.OverflowInt(n.Int64()) {
+			return fmt.Errorf("toon: integer %v overflows %s", n, dst.Type())
 		}
-		return fmt.Errorf("toon: cannot assign %T to int", src)
+		dst.SetInt(n.Int64())
+		return nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		if num, ok := toFloat64(src); ok {
-			if math.Trunc(num) != num {
-				return fmt.Errorf("toon: cannot assign non-integer %v to %s", num, dst.Type())
-			}
-			if num < 0 {
-				return fmt.Errorf("toon: cannot assign negative %v to %s", num, dst.Type())
-			}
-			uintVal := uint64(num)
-			if dst.OverflowUint(uintVal) {
-				return fmt.Errorf("toon: integer %v overflows %s", num, dst.Type())
-			}
-			dst.SetUint(uintVal)
-			return nil
+		n, ok, err := integerValue(src, dst.Type(), cfg.strictIntegers)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("toon: cannot assign %T to uint", plainValue(src))
+		}
+		if n.Sign() < 0 {
+			return fmt.Errorf("toon: cannot assign negative %v to %s", n, dst.Type())
 		}
-		return fmt.Errorf("toon: cannot assign %T to uint", src)
+		if !n.IsUint64() || dst.OverflowUint(n.Uint64()) {
+			return fmt.Errorf("toon: integer %v overflows %s", n, dst.Type())
+		}
+		dst.SetUint(n.Uint64())
+		return nil
 	default:
 		return fmt.Errorf("toon: unsupported destination kind %s", dst.Kind())
 	}
 }
 
+// integerValue converts a decoded number to an exact integer. Number literals
+// are evaluated from their source text, so no precision is lost to float64,
+// and quoted integers beyond ±maxSafeInteger, which the encoder emits as
+// strings, are accepted. ok is false when src is not numeric. With
+// strictSources, literals written with a fraction or exponent are rejected
+// even when their value is integral.
+func integerValue(src any, typ reflect.Type, strictSources bool) (*big.Int, bool, error) {
+	var r *big.Rat
+	display := fmt.Sprint(src)
+	switch val := src.(type) {
+	case numberLiteral:
+		if strictSources && strings.ContainsAny(val.text, ".eE") {
+			return nil, false, fmt.Errorf("toon: cannot assign float literal %s to %s (strict integer sources)", val.text, typ)
+		}
+		var ok bool
+		if r, ok = new(big.Rat).SetString(val.text); !ok {
+			// big.Rat rejects exponents too large to expand. The decoder has
+			// already refused such literals beyond float64 range, so this one
+			// lies far below 1 and must not fall back to its float64 value, 0.
+			return nil, false, fmt.Errorf("toon: cannot assign non-integer %s to %s", val.text, typ)
+		}
+		display = val.text
+	case string:
+		n, ok := new(big.Int).SetString(val, 10)
+		if !ok || n.CmpAbs(big.NewInt(maxSafeInteger)) <= 0 {
+			return nil, false, nil
+		}
+		return n, true, nil
+	}
+	if r == nil {
+		num, ok := toFloat64(src)
+		if !ok {
+			return nil, false, nil
+		}
+		r = new(big.Rat).SetFloat64(num)
+		if r == nil {
+			return nil, false, fmt.Errorf("toon: cannot assign non-integer %v to %s", num, typ)
+		}
+	}
+	if !r.IsInt() {
+		return nil, false, fmt.Errorf("toon: cannot assign non-integer %s to %s", display, typ)
+	}
+	return r.Num(), true, nil
+}
+
 func toFloat64(v any) (float64, bool) {
 	switch num := v.(type) {
+	case numberLiteral:
+		return num.value, true
 	case float64:
 		return num, true
 	case float32:
@@ -209,3 +373,128 @@ func toFloat64(v any) (float64, bool) {
 		return 0, false
 	}
 }
+
+// keyPath extends path with an object key. Paths are only tracked when an
+// unknown field handler or DecodeFields needs them.
+func keyPath(cfg decoderOptions, path, key string) string {
+	if !cfg.tracksPaths() {
+		return ""
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// indexPath extends path with an array index.
+func indexPath(cfg decoderOptions, path string, index int) string {
+	if !cfg.tracksPaths() {
+		return ""
+	}
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+var objectType = reflect.TypeOf(Object{})
+
+// asObject returns the fields of a decoded object. Unmarshal decodes objects
+// as Object values in document order; map[string]any sources are accepted
+// with their keys sorted.
+func asObject(src any) (Object, bool) {
+	switch obj := src.(type) {
+	case Object:
+		return obj, true
+	case map[string]any:
+		fields := make([]Field, 0, len(obj))
+		for key, value := range obj {
+			fields = append(fields, Field{Key: key, Value: value})
+		}
+		slices.SortFunc(fields, func(a, b Field) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+		return Object{Fields: fields}, true
+	default:
+		return Object{}, false
+	}
+}
+
+// exportMode selects how exportValue renders Unmarshal's internal values.
+type exportMode int
+
+const (
+	// exportPlain yields map[string]any objects and float64 numbers, as
+	// returned by Decode.
+	exportPlain exportMode = iota
+	// exportOrdered keeps Object values and yields float64 numbers.
+	exportOrdered
+	// exportJSON yields map[string]any objects and json.Number numbers.
+	exportJSON
+	// exportOrderedJSON keeps Object values and yields json.Number numbers.
+	exportOrderedJSON
+)
+
+// exportValue converts the Object and numberLiteral values produced for
+// Unmarshal into public Go values.
+func exportValue(v any, mode exportMode) any {
+	switch val := v.(type) {
+	case numberLiteral:
+		if mode == exportJSON || mode == exportOrderedJSON {
+			return json.Number(val.text)
+		}
+		return val.value
+	case Object:
+		if mode == exportOrdered || mode == exportOrderedJSON {
+			fields := make([]Field, len(val.Fields))
+			for i, field := range val.Fields {
+				fields[i] = Field{Key: field.Key, Value: exportValue(field.Value, mode)}
+			}
+			return Object{Fields: fields}
+		}
+		result := make(map[string]any, len(val.Fields))
+		for _, field := range val.Fields {
+			result[field.Key] = exportValue(field.Value, mode)
+		}
+		return result
+	case []any:
+		result := make([]any, len(val))
+		for i, item := range val {
+			result[i] = exportValue(item, mode)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func plainValue(v any) any {
+	return exportValue(v, exportPlain)
+}
+
+// assignHex decodes the hex string src into a byte slice or array field
+// tagged with the hex option. A fixed array requires exactly its length in
+// bytes, and null resets the field.
+func assignHex(dst reflect.Value, src any, _ decoderOptions, _ string) error {
+	if (dst.Kind() != reflect.Slice && dst.Kind() != reflect.Array) || dst.Type().Elem().Kind() != reflect.Uint8 {
+		return fmt.Errorf("toon: hex option requires a byte slice or array, got %s", dst.Type())
+	}
+	if src == nil {
+		dst.SetZero()
+		return nil
+	}
+	str, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("toon: expected hex string, got %T", plainValue(src))
+	}
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return fmt.Errorf("toon: invalid hex string: %w", err)
+	}
+	if dst.Kind() == reflect.Slice {
+		dst.SetBytes(b)
+		return nil
+	}
+	if len(b) != dst.Len() {
+		return fmt.Errorf("toon: array length mismatch: expected %d bytes, got %d", dst.Len(), len(b))
+	}
+	reflect.Copy(dst, reflect.ValueOf(b))
+	return nil
+}
@@ -0,0 +1,54 @@
+package codec
+
+import "io"
+
+// WriterEncoder writes TOON documents to an io.Writer, mirroring the
+// ergonomics of encoding/json's Encoder.
+type WriterEncoder struct {
+	w       io.Writer
+	cfg     encoderOptions
+	buf     []byte
+	started bool
+}
+
+// documentSeparator is the line that separates the documents of a stream.
+const documentSeparator = "---"
+
+// NewWriterEncoder returns an encoder that writes to w using the supplied
+// options.
+func NewWriterEncoder(w io.Writer, opts ...EncoderOption) *WriterEncoder {
+	return &WriterEncoder{w: w, cfg: NewEncoder(opts...).cfg}
+}
+
+// SetIndent sets the number of spaces per indentation level for subsequent
+// calls to Encode. Values below one are ignored.
+func (e *WriterEncoder) SetIndent(spaces int) {
+	WithIndent(spaces)(&e.cfg)
+}
+
+// SetDelimiter sets the array delimiter for subsequent calls to Encode.
+// Unsupported delimiters are ignored.
+func (e *WriterEncoder) SetDelimiter(delimiter Delimiter) {
+	WithArrayDelimiter(delimiter)(&e.cfg)
+}
+
+// Encode writes the TOON document for v followed by a newline. Each later
+// document is preceded by a line holding only "---", so that successive calls
+// produce a stream that ReaderDecoder reads back one document at a time. A
+// root string "---" is quoted so that it cannot be taken for the separator.
+func (e *WriterEncoder) Encode(v any) error {
+	buf := e.buf[:0]
+	if e.started {
+		buf = append(buf, documentSeparator+"\n"...)
+	}
+	enc := Encoder{cfg: e.cfg}
+	buf, err := enc.AppendMarshal(buf, v)
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+	e.buf = buf
+	e.started = true
+	_, err = e.w.Write(buf)
+	return err
+}
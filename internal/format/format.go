@@ -11,6 +11,9 @@ type Context struct {
 	Active   rune
 	Document rune
 	InArray  bool
+	// EscapeDelimiter lets FormatString backslash-escape the active delimiter
+	// instead of quoting a string that needs quoting for no other reason.
+	EscapeDelimiter bool
 }
 
 // FormatString applies TOON quoting rules to the provided string.
@@ -19,11 +22,25 @@ func FormatString(s string, ctx Context) (string, error) {
 		return "", err
 	}
 	if NeedsQuoting(s, ctx) {
+		if ctx.EscapeDelimiter && ctx.InArray && ctx.Active != 0 {
+			plain := ctx
+			plain.Active = 0
+			if !NeedsQuoting(s, plain) {
+				return EscapeDelimiter(s, ctx.Active), nil
+			}
+		}
 		return QuoteString(s)
 	}
 	return s, nil
 }
 
+// EscapeDelimiter prefixes every occurrence of delimiter in s with a
+// backslash.
+func EscapeDelimiter(s string, delimiter rune) string {
+	d := string(delimiter)
+	return strings.ReplaceAll(s, d, "\\"+d)
+}
+
 // NeedsQuoting reports whether the string must be quoted in the supplied context.
 func NeedsQuoting(s string, ctx Context) bool {
 	if len(s) == 0 {
@@ -48,6 +65,9 @@ func NeedsQuoting(s string, ctx Context) bool {
 	if strings.ContainsRune(s, '\n') || strings.ContainsRune(s, '\r') || strings.ContainsRune(s, '\t') {
 		return true
 	}
+	// A leading hyphen reads as a list-item marker wherever the value appears,
+	// so it is quoted in every context. Numeric strings such as "-5" were
+	// already quoted by LooksNumeric above.
 	if strings.HasPrefix(s, "-") {
 		return true
 	}
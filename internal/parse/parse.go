@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // UnquoteString removes surrounding quotes and unescapes TOON strings.
@@ -44,24 +45,44 @@ func UnquoteString(token string) (string, error) {
 	return b.String(), nil
 }
 
-// SplitInlineValues tokenizes a delimiter-separated list, respecting quoted segments.
-func SplitInlineValues(segment string, delimiter rune) ([]string, error) {
+// SplitInlineValues tokenizes a delimiter-separated list, respecting quoted
+// segments. When escapedDelimiters is set, a backslash followed by the
+// delimiter outside quotes yields a literal delimiter within the token.
+func SplitInlineValues(segment string, delimiter rune, escapedDelimiters bool) ([]string, error) {
+	return AppendInlineValues(nil, segment, delimiter, escapedDelimiters)
+}
+
+// AppendInlineValues is SplitInlineValues appending the tokens to dst, so
+// callers that split many rows can reuse one slice.
+func AppendInlineValues(dst []string, segment string, delimiter rune, escapedDelimiters bool) ([]string, error) {
 	if strings.TrimSpace(segment) == "" {
-		return nil, nil
+		return dst, nil
 	}
-	var tokens []string
+	if !escapedDelimiters || !strings.ContainsRune(segment, '\\') {
+		return appendInlineSlices(dst, segment, delimiter)
+	}
+	tokens := dst
 	var current strings.Builder
 	inQuotes := false
 	escaped := false
+	delimiterEscaped := false
 
 	for _, r := range segment {
 		switch {
+		case delimiterEscaped:
+			if r != delimiter {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+			delimiterEscaped = false
 		case escaped:
 			current.WriteRune(r)
 			escaped = false
 		case r == '\\' && inQuotes:
 			current.WriteRune(r)
 			escaped = true
+		case r == '\\' && escapedDelimiters:
+			delimiterEscaped = true
 		case r == '"':
 			current.WriteRune(r)
 			inQuotes = !inQuotes
@@ -75,6 +96,38 @@ func SplitInlineValues(segment string, delimiter rune) ([]string, error) {
 	if inQuotes {
 		return nil, errors.New("unterminated string in delimited values")
 	}
+	if delimiterEscaped {
+		current.WriteRune('\\')
+	}
 	tokens = append(tokens, strings.TrimSpace(current.String()))
 	return tokens, nil
 }
+
+// appendInlineSlices is AppendInlineValues for segments whose tokens need no
+// rewriting. Tokens are substrings of segment, so no token text is copied.
+func appendInlineSlices(tokens []string, segment string, delimiter rune) ([]string, error) {
+	if tokens == nil {
+		tokens = make([]string, 0, strings.Count(segment, string(delimiter))+1)
+	}
+	start := 0
+	inQuotes := false
+	escaped := false
+	for i, r := range segment {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == delimiter && !inQuotes:
+			tokens = append(tokens, strings.TrimSpace(segment[start:i]))
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated string in delimited values")
+	}
+	tokens = append(tokens, strings.TrimSpace(segment[start:]))
+	return tokens, nil
+}
@@ -1,7 +1,9 @@
 package toon_test
 
 import (
+	"iter"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -193,3 +195,437 @@ func TestRoundTripObjectListArrayFirstField(t *testing.T) {
 		t.Fatalf("unexpected decoded buckets: %#v", decoded.Buckets)
 	}
 }
+
+func TestRoundTripObjectListTabularFirstField(t *testing.T) {
+	type team struct {
+		Members []profile `toon:"members"`
+		Label   string    `toon:"label"`
+		Tags    []string  `toon:"tags"`
+	}
+	type league struct {
+		Teams []team `toon:"teams"`
+	}
+	payload := league{Teams: []team{
+		{Members: []profile{{ID: 1, Name: "Ada", Active: true}, {ID: 2, Name: "Bob"}}, Label: "alpha", Tags: []string{"x"}},
+		{Members: []profile{{ID: 3, Name: "Cy"}}, Label: "beta", Tags: []string{}},
+	}}
+
+	doc, err := toon.MarshalString(payload)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"teams[2]:",
+		"  - members[2]{id,name,active}:",
+		"    1,Ada,true",
+		"    2,Bob,false",
+		"    label: alpha",
+		"    tags[1]: x",
+		"  - members[1]{id,name,active}:",
+		"    3,Cy,false",
+		"    label: beta",
+		"    tags[0]:",
+	)
+
+	var decoded league
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("round trip mismatch:\n got: %+v\nwant: %+v", decoded, payload)
+	}
+
+	if _, err := toon.DecodeString("teams[1]:\n  - members[2]{id,name}:\n    1,Ada\n    label: alpha"); err == nil {
+		t.Fatalf("expected row count mismatch when a sibling follows too few rows")
+	}
+}
+
+func TestMarshalPointerSliceWithNil(t *testing.T) {
+	payload := struct {
+		Users []*profile `toon:"users"`
+	}{
+		Users: []*profile{
+			{ID: 1, Name: "Ada", Active: true},
+			nil,
+			{ID: 3, Name: "Cy", Active: false},
+		},
+	}
+
+	doc, err := toon.MarshalString(payload)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"users[3]:",
+		"  - id: 1",
+		"    name: Ada",
+		"    active: true",
+		"  - null",
+		"  - id: 3",
+		"    name: Cy",
+		"    active: false",
+	)
+
+	doc, err = toon.MarshalString(payload, toon.WithTabularFill(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"users[3]{id,name,active}:",
+		"  1,Ada,true",
+		"  null,null,null",
+		"  3,Cy,false",
+	)
+
+	payload.Users = []*profile{nil}
+	doc, err = toon.MarshalString(payload, toon.WithTabularFill(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc, "users[1]: null")
+}
+
+func TestTabularFillValue(t *testing.T) {
+	type cell struct {
+		Name string  `toon:"name"`
+		Note *string `toon:"note"`
+	}
+	payload := struct {
+		Rows []*cell `toon:"rows"`
+	}{
+		Rows: []*cell{{Name: "Ada"}, nil, {Name: ""}},
+	}
+
+	doc, err := toon.MarshalString(payload, toon.WithTabularFill(true), toon.WithTabularFillValue("-"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"rows[3]{name,note}:",
+		"  Ada,null",
+		`  "-","-"`,
+		`  "",null`,
+	)
+
+	var out struct {
+		Rows []*cell `toon:"rows"`
+	}
+	if err := toon.UnmarshalString(doc, &out, toon.WithDecoderTabularFillValue("-")); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if len(out.Rows) != 3 || out.Rows[0] == nil || out.Rows[1] != nil || out.Rows[2] == nil || out.Rows[2].Name != "" {
+		t.Fatalf("unexpected rows: %#v", out.Rows)
+	}
+
+	decoded, err := toon.DecodeString("rows[2]{a,b}:\n  -1,-1\n  -1,2", toon.WithDecoderTabularFillValue(-1))
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if rows := decoded.(map[string]any)["rows"].([]any); rows[0] != nil || rows[1] == nil {
+		t.Fatalf("unexpected rows: %#v", rows)
+	}
+
+	if _, err := toon.MarshalString(payload, toon.WithTabularFill(true), toon.WithTabularFillValue([]int{1})); err == nil || !strings.Contains(err.Error(), "tabular fill value must be") {
+		t.Fatalf("expected fill value error, got %v", err)
+	}
+	if _, err := toon.MarshalString(map[string]int{"a": 1}, toon.WithTabularFillValue(struct{}{})); err == nil || !strings.Contains(err.Error(), "tabular fill value must be") {
+		t.Fatalf("expected fill value error without nil rows, got %v", err)
+	}
+}
+
+func TestMarshalIterators(t *testing.T) {
+	evens := func(yield func(int) bool) {
+		for i := 0; i < 10; i += 2 {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	scores := func(yield func(string, float64) bool) {
+		_ = yield("zed", 1.5) && yield("amy", 2)
+	}
+	users := slices.Values([]profile{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Bob"}})
+
+	doc, err := toon.MarshalString(map[string]any{
+		"evens":  iter.Seq[int](evens),
+		"scores": iter.Seq2[string, float64](scores),
+		"users":  users,
+		"none":   iter.Seq[int](nil),
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"evens[5]: 0,2,4,6,8",
+		"none: null",
+		"scores:",
+		"  zed: 1.5",
+		"  amy: 2",
+		"users[2]{id,name,active}:",
+		"  1,Ada,false",
+		"  2,Bob,false",
+	)
+
+	repeated := func(yield func(string, int) bool) {
+		_ = yield("a", 1) && yield("a", 2)
+	}
+	if _, err := toon.Marshal(iter.Seq2[string, int](repeated)); err == nil || !strings.Contains(err.Error(), `duplicate key "a"`) {
+		t.Fatalf("expected duplicate key error, got %v", err)
+	}
+
+	// A sequence that ignores yield's result must not replace the first error.
+	careless := func(yield func(any) bool) {
+		yield(make(chan int))
+		yield(1)
+	}
+	if _, err := toon.Marshal(iter.Seq[any](careless)); err == nil || !strings.Contains(err.Error(), "unsupported type") {
+		t.Fatalf("expected the first error to stand, got %v", err)
+	}
+
+	indexed := func(yield func(int, string) bool) {}
+	if _, err := toon.Marshal(iter.Seq2[int, string](indexed)); err == nil || !strings.Contains(err.Error(), "unsupported type") {
+		t.Fatalf("expected unsupported type error, got %v", err)
+	}
+}
+
+func TestSortTabularColumns(t *testing.T) {
+	rows := []toon.Object{
+		toon.NewObject(toon.Field{Key: "name", Value: "Ada"}, toon.Field{Key: "id", Value: 1}, toon.Field{Key: "age", Value: 36}),
+		toon.NewObject(toon.Field{Key: "id", Value: 2}, toon.Field{Key: "age", Value: 41}, toon.Field{Key: "name", Value: "Bob"}),
+	}
+
+	doc, err := toon.MarshalString(map[string]any{"users": rows})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"users[2]{name,id,age}:",
+		"  Ada,1,36",
+		"  Bob,2,41",
+	)
+
+	doc, err = toon.MarshalString(map[string]any{"users": rows}, toon.WithSortTabularColumns(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"users[2]{age,id,name}:",
+		"  36,1,Ada",
+		"  41,2,Bob",
+	)
+}
+
+func TestNestedSlicesRoundTrip(t *testing.T) {
+	type grid struct {
+		Matrix [][]int    `toon:"matrix"`
+		Words  [][]string `toon:"words"`
+	}
+	in := grid{
+		Matrix: [][]int{{1, 2}, {3, 4}, {}},
+		Words:  [][]string{{"a", "b c"}, {"d,e"}},
+	}
+
+	doc, err := toon.MarshalString(in)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"matrix[3]:",
+		"  - [2]: 1,2",
+		"  - [2]: 3,4",
+		"  - [0]:",
+		"words[2]:",
+		"  - [2]: a,b c",
+		"  - [1]: \"d,e\"",
+	)
+
+	var out grid
+	if err := toon.UnmarshalString(doc, &out); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("round trip mismatch:\n got: %#v\nwant: %#v", out, in)
+	}
+
+	var root [][]int
+	if err := toon.UnmarshalString("[2]:\n  - [2]: 1,2\n  - [2]: 3,4", &root); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(root, [][]int{{1, 2}, {3, 4}}) {
+		t.Fatalf("unexpected root matrix: %#v", root)
+	}
+}
+
+func TestEmptyObjectListItemRoundTrip(t *testing.T) {
+	payload := map[string]any{
+		"items": []any{map[string]any{}, map[string]any{"id": float64(1)}, "{}", "[]"},
+	}
+
+	doc, err := toon.MarshalString(payload)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"items[4]:",
+		"  - {}",
+		"  - id: 1",
+		"  - \"{}\"",
+		"  - \"[]\"",
+	)
+
+	decoded, err := toon.DecodeString(doc)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("round trip mismatch: %#v", decoded)
+	}
+
+	var typed struct {
+		Items []struct {
+			ID int `toon:"id"`
+		} `toon:"items"`
+	}
+	if err := toon.UnmarshalString("items[2]:\n  - {}\n  - id: 2", &typed); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if len(typed.Items) != 2 || typed.Items[0].ID != 0 || typed.Items[1].ID != 2 {
+		t.Fatalf("unexpected items: %#v", typed.Items)
+	}
+}
+
+func TestNestedObjectFirstFieldListItemRoundTrip(t *testing.T) {
+	payload := toon.NewObject(toon.Field{Key: "items", Value: []any{
+		toon.NewObject(
+			toon.Field{Key: "meta", Value: toon.NewObject(toon.Field{Key: "id", Value: 1})},
+			toon.Field{Key: "name", Value: "Ada"},
+		),
+		toon.NewObject(toon.Field{Key: "empty", Value: toon.NewObject()}),
+	}})
+
+	doc, err := toon.MarshalString(payload)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"items[2]:",
+		"  - meta:",
+		"      id: 1",
+		"    name: Ada",
+		"  - empty:",
+	)
+
+	decoded, err := toon.DecodeString(doc)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	want := map[string]any{"items": []any{
+		map[string]any{"meta": map[string]any{"id": float64(1)}, "name": "Ada"},
+		map[string]any{"empty": map[string]any{}},
+	}}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("round trip mismatch: %#v", decoded)
+	}
+}
+
+func TestDecodeEmptyLiterals(t *testing.T) {
+	doc := strings.Join([]string{
+		"obj: {}",
+		"arr: []",
+		"list[2]:",
+		"  - []",
+		"  - key: {}",
+		"    other: []",
+	}, "\n")
+
+	decoded, err := toon.DecodeString(doc)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	want := map[string]any{
+		"obj": map[string]any{},
+		"arr": []any{},
+		"list": []any{
+			[]any{},
+			map[string]any{"key": map[string]any{}, "other": []any{}},
+		},
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("unexpected decode: %#v", decoded)
+	}
+
+	cells, err := toon.DecodeString("cells[2]: {},[]")
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if !reflect.DeepEqual(cells, map[string]any{"cells": []any{"{}", "[]"}}) {
+		t.Fatalf("inline cells should stay primitives: %#v", cells)
+	}
+}
+
+func TestRootSliceRoundTrip(t *testing.T) {
+	users := []profile{
+		{ID: 1, Name: "Ada", Active: true},
+		{ID: 2, Name: "Bob"},
+	}
+	doc, err := toon.Marshal(users)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.HasPrefix(string(doc), "[2]{id,name,active}:") {
+		t.Fatalf("expected keyless tabular root, got:\n%s", doc)
+	}
+
+	var decoded []profile
+	if err := toon.Unmarshal(doc, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, users) {
+		t.Fatalf("round trip mismatch: %#v", decoded)
+	}
+
+	var pointers []*profile
+	if err := toon.Unmarshal(doc, &pointers); err != nil {
+		t.Fatalf("Unmarshal pointers: %v", err)
+	}
+	if len(pointers) != 2 || *pointers[1] != users[1] {
+		t.Fatalf("unexpected pointers: %#v", pointers)
+	}
+
+	var fixed [2]profile
+	if err := toon.Unmarshal(doc, &fixed); err != nil {
+		t.Fatalf("Unmarshal array: %v", err)
+	}
+	if fixed[0] != users[0] {
+		t.Fatalf("unexpected array: %#v", fixed)
+	}
+
+	email := "ada@example.com"
+	mixed := []profile{{ID: 1, Name: "Ada", Email: &email}, {ID: 2, Name: "Bob"}}
+	doc, err = toon.Marshal(mixed)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.HasPrefix(string(doc), "[2]:\n  - ") {
+		t.Fatalf("expected keyless list root, got:\n%s", doc)
+	}
+	decoded = nil
+	if err := toon.Unmarshal(doc, &decoded); err != nil {
+		t.Fatalf("Unmarshal list: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, mixed) {
+		t.Fatalf("list round trip mismatch: %#v", decoded)
+	}
+
+	doc, err = toon.Marshal([]profile{})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	decoded = nil
+	if err := toon.Unmarshal(doc, &decoded); err != nil {
+		t.Fatalf("Unmarshal empty: %v", err)
+	}
+	if decoded == nil || len(decoded) != 0 {
+		t.Fatalf("expected empty non-nil slice, got %#v", decoded)
+	}
+}
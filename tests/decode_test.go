@@ -1,6 +1,7 @@
 package toon_test
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -26,6 +27,62 @@ func TestDecodeRootForms(t *testing.T) {
 	}
 }
 
+func TestDecodeKeylessRootArrays(t *testing.T) {
+	users := []profile{
+		{ID: 1, Name: "Ada", Active: true},
+		{ID: 2, Name: "Bob"},
+	}
+	doc, err := toon.MarshalString(users)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"[2]{id,name,active}:",
+		"  1,Ada,true",
+		"  2,Bob,false",
+	)
+	value, err := toon.DecodeString(doc)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	want := []any{
+		map[string]any{"id": float64(1), "name": "Ada", "active": true},
+		map[string]any{"id": float64(2), "name": "Bob", "active": false},
+	}
+	if !reflect.DeepEqual(value, want) {
+		t.Fatalf("unexpected tabular root: %#v", value)
+	}
+
+	cases := []struct {
+		doc  string
+		want any
+	}{
+		{"[#2|]{a|b}:\n  1|x\n  2|y", []any{
+			map[string]any{"a": float64(1), "b": "x"},
+			map[string]any{"a": float64(2), "b": "y"},
+		}},
+		{"\n[3]:\n  - 1\n  - a: 2\n  - [1]: 3\n", []any{
+			float64(1), map[string]any{"a": float64(2)}, []any{float64(3)},
+		}},
+		{"[0]:", []any{}},
+	}
+	for _, tc := range cases {
+		value, err := toon.DecodeString(tc.doc)
+		if err != nil {
+			t.Fatalf("DecodeString(%q): %v", tc.doc, err)
+		}
+		if !reflect.DeepEqual(value, tc.want) {
+			t.Fatalf("DecodeString(%q) = %#v, want %#v", tc.doc, value, tc.want)
+		}
+	}
+
+	for _, doc := range []string{"[1]: 1\nfoo: 2", "[1]:\n  - 1\n[1]: 2", "[1]{a}:\n  1\n\nx"} {
+		if _, err := toon.DecodeString(doc); err == nil || !strings.Contains(err.Error(), "after root array") {
+			t.Fatalf("expected trailing content error for %q, got %v", doc, err)
+		}
+	}
+}
+
 func TestDecodeStrictErrors(t *testing.T) {
 	cases := []struct {
 		name string
@@ -67,6 +124,32 @@ func TestDecodeWithCustomDocumentDelimiter(t *testing.T) {
 	}
 }
 
+func TestDecodeDelimiterFallback(t *testing.T) {
+	doc := "tags[3|]: a,b,c\nrows[2|]{id|name}:\n  1,Ada\n  2|Bob"
+	permissive := []toon.DecoderOption{toon.WithStrictMode(false), toon.WithDelimiterFallback(true)}
+
+	root := decodeMap(t, doc, permissive...)
+	want := map[string]any{
+		"tags": []any{"a", "b", "c"},
+		"rows": []any{
+			map[string]any{"id": float64(1), "name": "Ada"},
+			map[string]any{"id": float64(2), "name": "Bob"},
+		},
+	}
+	if !reflect.DeepEqual(root, want) {
+		t.Fatalf("unexpected value: %#v", root)
+	}
+
+	root = decodeMap(t, doc, toon.WithStrictMode(false))
+	if tags := root["tags"].([]any); len(tags) != 1 || tags[0] != "a,b,c" {
+		t.Fatalf("expected no fallback without the option, got %#v", tags)
+	}
+
+	if _, err := toon.DecodeString(doc, toon.WithDelimiterFallback(true)); err == nil || !strings.Contains(err.Error(), "length mismatch") {
+		t.Fatalf("strict mode should report the mismatch, got %v", err)
+	}
+}
+
 func TestDecoderIndentOption(t *testing.T) {
 	doc := strings.Join([]string{
 		"items[1]:",
@@ -81,3 +164,448 @@ func TestDecoderIndentOption(t *testing.T) {
 		t.Fatalf("permissive tab decode failed: %v", err)
 	}
 }
+
+func TestDecoderIndentStep(t *testing.T) {
+	payload := map[string]any{
+		"a":    map[string]any{"b": map[string]any{"c": float64(1)}},
+		"rows": []any{map[string]any{"x": float64(1)}, map[string]any{"x": float64(2)}},
+		"list": []any{float64(1), map[string]any{"id": float64(1), "meta": map[string]any{"z": float64(1)}}},
+	}
+
+	for _, step := range []int{2, 3, 4, 8} {
+		doc, err := toon.MarshalString(payload, toon.WithIndent(step))
+		if err != nil {
+			t.Fatalf("MarshalString: %v", err)
+		}
+		decoded, err := toon.DecodeString(doc, toon.WithAutoIndent(true))
+		if err != nil {
+			t.Fatalf("step %d: DecodeString: %v", step, err)
+		}
+		if !reflect.DeepEqual(decoded, payload) {
+			t.Fatalf("step %d: round trip mismatch: %#v", step, decoded)
+		}
+	}
+
+	doc := "a:\n    b: 1"
+	_, err := toon.DecodeString(doc)
+	if err == nil || !strings.Contains(err.Error(), "WithDecoderIndent(4)") {
+		t.Fatalf("expected indentation step error, got %v", err)
+	}
+	if _, err := toon.DecodeString(doc, toon.WithDecoderIndent(4)); err != nil {
+		t.Fatalf("explicit indent: %v", err)
+	}
+	if _, err := toon.DecodeString("a:\n    b: 1\n  c: 2", toon.WithAutoIndent(true)); err == nil {
+		t.Fatalf("expected error for line off the detected step")
+	}
+	if v, err := toon.DecodeString("    42"); err != nil || v != float64(42) {
+		t.Fatalf("indented single value should decode, got %#v (%v)", v, err)
+	}
+}
+
+func TestDecodeTabIndentation(t *testing.T) {
+	doc := "a:\n\tb: 1\n\tc:\n\t\td: x\nitems[2]:\n\t- 1\n\t- 2"
+	if _, err := toon.DecodeString(doc); err == nil {
+		t.Fatalf("expected tabs to be rejected without WithTabIndentation")
+	}
+	decoded, err := toon.DecodeString(doc, toon.WithTabIndentation(true))
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	want := map[string]any{
+		"a":     map[string]any{"b": float64(1), "c": map[string]any{"d": "x"}},
+		"items": []any{float64(1), float64(2)},
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("unexpected value: %#v", decoded)
+	}
+
+	mixed := "a:\n\t  b: 1"
+	if _, err := toon.DecodeString(mixed, toon.WithTabIndentation(true)); err == nil || !strings.Contains(err.Error(), "mixed tabs and spaces") {
+		t.Fatalf("expected mixed indentation error, got %v", err)
+	}
+	if _, err := toon.DecodeString("a:\n  b: 1", toon.WithTabIndentation(true), toon.WithStrictMode(false)); err != nil {
+		t.Fatalf("permissive mode should accept spaces: %v", err)
+	}
+}
+
+func TestUnmarshalIntoObjectPreservesOrder(t *testing.T) {
+	doc := strings.Join([]string{
+		"zeta: 1",
+		"alpha:",
+		"  mid: x",
+		"  first: y",
+		"rows[2]{b,a}:",
+		"  1,2",
+		"  3,4",
+		"extra:",
+		"  k: v",
+	}, "\n")
+
+	var obj toon.Object
+	if err := toon.UnmarshalString(doc, &obj); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	keys := make([]string, 0, obj.Len())
+	for _, field := range obj.Fields {
+		keys = append(keys, field.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"zeta", "alpha", "rows", "extra"}) {
+		t.Fatalf("unexpected key order: %v", keys)
+	}
+	nested, ok := obj.Fields[1].Value.(toon.Object)
+	if !ok || nested.Fields[0].Key != "mid" || nested.Fields[1].Key != "first" {
+		t.Fatalf("nested object not ordered: %#v", obj.Fields[1].Value)
+	}
+	row := obj.Fields[2].Value.([]any)[0].(toon.Object)
+	if row.Fields[0].Key != "b" || row.Fields[1].Key != "a" {
+		t.Fatalf("tabular row not ordered: %#v", row)
+	}
+
+	var mixed struct {
+		Alpha toon.Object `toon:"alpha"`
+		Extra any         `toon:"extra"`
+	}
+	if err := toon.UnmarshalString(doc, &mixed); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if mixed.Alpha.Fields[0].Key != "mid" {
+		t.Fatalf("struct Object field not ordered: %#v", mixed.Alpha)
+	}
+	if !reflect.DeepEqual(mixed.Extra, map[string]any{"k": "v"}) {
+		t.Fatalf("interface field should decode as map: %#v", mixed.Extra)
+	}
+}
+
+func TestDecoderElements(t *testing.T) {
+	dec := toon.NewDecoder()
+
+	cases := map[string]string{
+		"inline":  "[3]: 1,2,3",
+		"tabular": "[3]{n}:\n  1\n  2\n  3",
+		"list":    "[3]:\n  - 1\n  - n: 2\n  - [1]: 3",
+	}
+	for name, doc := range cases {
+		t.Run(name, func(t *testing.T) {
+			elements := dec.Elements([]byte(doc))
+			count := 0
+			for i, value := range elements.All() {
+				if i != count || value == nil {
+					t.Fatalf("unexpected element %d: %#v", i, value)
+				}
+				count++
+			}
+			if err := elements.Err(); err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			if count != 3 {
+				t.Fatalf("expected 3 elements, got %d", count)
+			}
+		})
+	}
+
+	t.Run("early break", func(t *testing.T) {
+		elements := dec.Elements([]byte("[3]:\n  - a\n  - b\n  - :bad"))
+		for i := range elements.All() {
+			if i == 1 {
+				break
+			}
+		}
+		if err := elements.Err(); err != nil {
+			t.Fatalf("Err after break: %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		elements := dec.Elements([]byte("[2]:\n  - a\n  - b\n  - c"))
+		var seen []any
+		for _, value := range elements.All() {
+			seen = append(seen, value)
+		}
+		if elements.Err() == nil || len(seen) != 3 {
+			t.Fatalf("expected length error after 3 elements, got %v (%v)", seen, elements.Err())
+		}
+
+		elements = dec.Elements([]byte("key: value"))
+		for range elements.All() {
+			t.Fatalf("unexpected element for object root")
+		}
+		if elements.Err() == nil {
+			t.Fatalf("expected error for object root")
+		}
+
+		for _, doc := range []string{"[1]: 1\nextra: 2", "[1]:\n  - 1\nextra: 2"} {
+			elements = dec.Elements([]byte(doc))
+			for range elements.All() {
+			}
+			if elements.Err() == nil {
+				t.Fatalf("expected trailing content error for %q", doc)
+			}
+		}
+	})
+}
+
+func FuzzDecode(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"a: 1",
+		"user:\n  id: 1\n  name: Ada",
+		"items[3]: 1,2,3",
+		"items[2|]: a|b",
+		"users[2]{id,name}:\n  1,Ada\n  2,Bob",
+		"items[2]:\n  - a: 1\n    b: 2\n  - [2]: x,y",
+		"[2]: 1,2",
+		"\"quoted key\": \"value\"",
+		"a.b.c: 1",
+		"- x",
+		"key[",
+		"\"",
+		"a:\n    b: 1\n  c: 2",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = toon.Decode(data)
+		_, _ = toon.Decode(data, toon.WithStrictMode(false), toon.WithDottedKeyExpansion(true))
+		var v any
+		_ = toon.Unmarshal(data, &v, toon.WithAutoIndent(true), toon.WithExtendedNumbers(true), toon.WithAllowNonFinite(true))
+		var payload usersPayload
+		_ = toon.Unmarshal(data, &payload)
+		_, _ = toon.Tokenize(data)
+		it := toon.NewDecoder().Elements(data)
+		for range it.All() {
+		}
+	})
+}
+
+func TestDecodeFragment(t *testing.T) {
+	doc := "    server:\n      host: localhost\n      ports[2]: 80,443\n\n    users[2]{id,name}:\n      1,Ada\n      2,Bob\n"
+	got, err := toon.DecodeFragment([]byte(doc))
+	if err != nil {
+		t.Fatalf("DecodeFragment: %v", err)
+	}
+	want := map[string]any{
+		"server": map[string]any{"host": "localhost", "ports": []any{float64(80), float64(443)}},
+		"users": []any{
+			map[string]any{"id": float64(1), "name": "Ada"},
+			map[string]any{"id": float64(2), "name": "Bob"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected fragment: %#v", got)
+	}
+
+	if _, err := toon.Decode([]byte(doc)); err == nil {
+		t.Fatalf("expected Decode to reject the indented fragment")
+	}
+
+	plain, err := toon.DecodeFragment([]byte("a: 1"))
+	if err != nil || !reflect.DeepEqual(plain, map[string]any{"a": float64(1)}) {
+		t.Fatalf("unindented fragment: %#v, %v", plain, err)
+	}
+
+	_, err = toon.DecodeFragment([]byte("  a:\n     b: 1"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Fatalf("expected error on line 2, got %v", err)
+	}
+}
+
+func TestDecodeSpans(t *testing.T) {
+	doc := "name: demo\nconfig:\n  retries: 3\n  hosts[2]: a,b\n\nusers[2]{id,name}:\n  1,Ada\n  2,Bob\nitems[2]:\n  - id: 1\n    tags[1]: x\n  - 5\n"
+	value, spans, err := toon.NewDecoder().DecodeSpans([]byte(doc))
+	if err != nil {
+		t.Fatalf("DecodeSpans: %v", err)
+	}
+	if value.(map[string]any)["name"] != "demo" {
+		t.Fatalf("unexpected value: %#v", value)
+	}
+	want := map[string]toon.Span{
+		"":              {StartLine: 1, EndLine: 12},
+		"config":        {StartLine: 2, EndLine: 4},
+		"config.hosts":  {StartLine: 4, EndLine: 4},
+		"users":         {StartLine: 6, EndLine: 8},
+		"users[0]":      {StartLine: 7, EndLine: 7},
+		"users[1]":      {StartLine: 8, EndLine: 8},
+		"items":         {StartLine: 9, EndLine: 12},
+		"items[0]":      {StartLine: 10, EndLine: 11},
+		"items[0].tags": {StartLine: 11, EndLine: 11},
+	}
+	if !reflect.DeepEqual(spans, want) {
+		t.Fatalf("spans mismatch:\nwant %v\ngot  %v", want, spans)
+	}
+}
+
+func BenchmarkDecodeLargeTabular(b *testing.B) {
+	var sb strings.Builder
+	const rows = 20000
+	sb.WriteString("rows[20000]{id,name,score}:\n")
+	for i := 0; i < rows; i++ {
+		sb.WriteString("  1,alpha,3.5\n")
+	}
+	doc := []byte(sb.String())
+	b.ReportAllocs()
+	b.SetBytes(int64(len(doc)))
+	for i := 0; i < b.N; i++ {
+		if _, err := toon.Decode(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDecodeRows(t *testing.T) {
+	rows, err := toon.DecodeRows([]byte("[2]{id,name}:\n  1,Ada\n  2,Bob"))
+	if err != nil {
+		t.Fatalf("DecodeRows: %v", err)
+	}
+	want := []map[string]any{
+		{"id": float64(1), "name": "Ada"},
+		{"id": float64(2), "name": "Bob"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("unexpected rows: %#v", rows)
+	}
+
+	rows, err = toon.DecodeRowsAt([]byte("count: 2\nusers[2]:\n  - id: 1\n    name: Ada\n  - id: 2\n    name: Bob"), "users")
+	if err != nil {
+		t.Fatalf("DecodeRowsAt: %v", err)
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("unexpected rows: %#v", rows)
+	}
+
+	// Rows are maps even when ordered objects are requested.
+	ordered := toon.WithOrderedObjects(true)
+	if rows, err = toon.DecodeRows([]byte("[2]{id,name}:\n  1,Ada\n  2,Bob"), ordered); err != nil || !reflect.DeepEqual(rows, want) {
+		t.Fatalf("DecodeRows with ordered objects: %#v (%v)", rows, err)
+	}
+	if rows, err = toon.DecodeRowsAt([]byte("users[2]{id,name}:\n  1,Ada\n  2,Bob"), "users", ordered); err != nil || !reflect.DeepEqual(rows, want) {
+		t.Fatalf("DecodeRowsAt with ordered objects: %#v (%v)", rows, err)
+	}
+
+	cases := map[string]struct {
+		doc, key, msg string
+	}{
+		"root object":   {doc: "a: 1", msg: "root is an object, not an array"},
+		"primitive row": {doc: "[2]: 1,2", msg: "root[0] is a number, not an object"},
+		"missing key":   {doc: "a: 1", key: "users", msg: `key "users" not found`},
+		"key not array": {doc: "users: x", key: "users", msg: `"users" is a string, not an array`},
+		"root array":    {doc: "[1]: 1", key: "users", msg: "root is an array, not an object"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			if tc.key == "" {
+				_, err = toon.DecodeRows([]byte(tc.doc))
+			} else {
+				_, err = toon.DecodeRowsAt([]byte(tc.doc), tc.key)
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.msg) {
+				t.Fatalf("expected %q, got %v", tc.msg, err)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	doc := []byte("users[2]{id,name}:\n  1,Ada\n  2,Bob\nmeta:\n  tags[2]: a,b\n  owner:\n    name: ops")
+	cases := map[string]any{
+		"users[1].name":   "Bob",
+		"users[0].id":     float64(1),
+		"meta.tags[1]":    "b",
+		"meta.owner.name": "ops",
+		"meta.owner":      map[string]any{"name": "ops"},
+	}
+	for path, want := range cases {
+		got, err := toon.Get(doc, path)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", path, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Get(%q) = %#v, want %#v", path, got, want)
+		}
+	}
+	ordered := toon.WithOrderedObjects(true)
+	if got, err := toon.Get(doc, "meta.owner.name", ordered); err != nil || got != "ops" {
+		t.Fatalf("Get with ordered objects: got %#v (%v)", got, err)
+	}
+	want := toon.NewObject(toon.Field{Key: "name", Value: "ops"})
+	if got, err := toon.Get(doc, "meta.owner", ordered); err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("Get with ordered objects: got %#v (%v)", got, err)
+	}
+	if _, err := toon.Get(doc, "meta.missing", ordered); err == nil || !strings.Contains(err.Error(), "meta.missing not found") {
+		t.Fatalf("Get with ordered objects: expected not found, got %v", err)
+	}
+	if root, err := toon.Get([]byte("[2]: x,y"), "[1]"); err != nil || root != "y" {
+		t.Fatalf("root index: got %#v (%v)", root, err)
+	}
+
+	errs := map[string]string{
+		"users[2].name": "users[2] out of range for length 2",
+		"meta.missing":  "meta.missing not found",
+		"users.name":    "users is an array, not an object",
+		"meta[0]":       "meta is an object, not an array",
+		"users[0]x":     "invalid path",
+		"users[-1]":     "bad index",
+		"meta..tags":    "empty key",
+		"users[0":       "unclosed [",
+	}
+	for path, msg := range errs {
+		_, err := toon.Get(doc, path)
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("Get(%q): expected %q, got %v", path, msg, err)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	doc := []byte("name: app\nserver:\n  port: 80\n  host: a\nitems[2]: x,y")
+
+	out, err := toon.Set(doc, "server.port", 8080)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	expectLines(t, string(out),
+		"name: app",
+		"server:",
+		"  port: 8080",
+		"  host: a",
+		"items[2]: x,y",
+	)
+
+	out, err = toon.Set([]byte("a: 9007199254740993\nb: 1.10\nname: x"), "name", "y")
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if want := "a: 9007199254740993\nb: 1.10\nname: y"; string(out) != want {
+		t.Fatalf("Set rewrote untouched numbers: got %q, want %q", out, want)
+	}
+
+	out, err = toon.Set(doc, "server.tls.enabled", true)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v, err := toon.Get(out, "server.tls.enabled"); err != nil || v != true {
+		t.Fatalf("created path: got %#v (%v)", v, err)
+	}
+
+	out, err = toon.Set(doc, "items[2]", "z")
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v, err := toon.Get(out, "items"); err != nil || !reflect.DeepEqual(v, []any{"x", "y", "z"}) {
+		t.Fatalf("append: got %#v (%v)", v, err)
+	}
+
+	errs := map[string]string{
+		"items[5]":   "items[5] out of range for length 2",
+		"name.first": "name is a string, not an object",
+		"server[0]":  "server is an object, not an array",
+		"missing[0]": "missing is null, not an array",
+		"items[0]]":  "invalid path",
+	}
+	for path, msg := range errs {
+		_, err := toon.Set(doc, path, 1)
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("Set(%q): expected %q, got %v", path, msg, err)
+		}
+	}
+}
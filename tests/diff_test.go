@@ -0,0 +1,108 @@
+package toon_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+func TestDiff(t *testing.T) {
+	a := "name: app\nserver:\n  host: localhost\n  port: 80\ntags[2]: a,b\nratio: 1.0\nmode: fast\n"
+	b := "name: app\nserver:\n  host: example.com\n  port: 80\n  tls: true\ntags[3]: a,c,d\nratio: 1\nmode:\n  speed: fast\n"
+
+	changes, err := toon.Diff([]byte(a), []byte(b))
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	want := []toon.Change{
+		{Kind: toon.ChangeModified, Path: "server.host", Old: "localhost", New: "example.com"},
+		{Kind: toon.ChangeAdded, Path: "server.tls", New: true},
+		{Kind: toon.ChangeModified, Path: "tags[1]", Old: "b", New: "c"},
+		{Kind: toon.ChangeAdded, Path: "tags[2]", New: "d"},
+		{Kind: toon.ChangeModified, Path: "mode", Old: "fast", New: map[string]any{"speed": "fast"}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("Diff mismatch:\nwant %#v\ngot  %#v", want, changes)
+	}
+}
+
+func TestDiffRemovedAndNull(t *testing.T) {
+	changes, err := toon.Diff([]byte("a: 1\nb: null\nitems[2]: 1,2"), []byte("b: null\nitems[1]: 1\nc: null"))
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	want := []toon.Change{
+		{Kind: toon.ChangeRemoved, Path: "a", Old: float64(1)},
+		{Kind: toon.ChangeRemoved, Path: "items[1]", Old: float64(2)},
+		{Kind: toon.ChangeAdded, Path: "c"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("Diff mismatch:\nwant %#v\ngot  %#v", want, changes)
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	doc := []byte("users[2]{id,name}:\n  1,Ada\n  2,Bob")
+	changes, err := toon.Diff(doc, doc)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected no changes, got %#v", changes)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := "name: app\nserver:\n  host: localhost\n  port: 80\ntags[2]: a,b\nusers[2]{id,name}:\n  1,Ada\n  2,Bob"
+	equivalent := []struct {
+		doc  string
+		opts []toon.DecoderOption
+	}{
+		{doc: "server:\n  port: 80\n  host: \"localhost\"\nname: app\ntags[2|]: a|b\nusers[2]:\n  - name: Ada\n    id: 1.0\n  - id: 2\n    name: Bob"},
+		{doc: "name: app\nserver:\n    host: localhost\n    port: 80\ntags[#2]: a,b\nusers[2]{id,name}:\n    1,Ada\n    2,Bob", opts: []toon.DecoderOption{toon.WithDecoderIndent(4)}},
+		{doc: strings.Replace(base, "port: 80", "port: 8.00e1", 1)},
+		{doc: base, opts: []toon.DecoderOption{toon.WithOrderedObjects(true), toon.WithUseNumber(true)}},
+	}
+
+	want, err := toon.Fingerprint([]byte(base))
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	for _, tc := range equivalent {
+		got, err := toon.Fingerprint([]byte(tc.doc), tc.opts...)
+		if err != nil {
+			t.Fatalf("Fingerprint(%q): %v", tc.doc, err)
+		}
+		if got != want {
+			t.Fatalf("fingerprint differs for equivalent document:\n%s", tc.doc)
+		}
+	}
+
+	for _, doc := range []string{
+		strings.Replace(base, "tags[2]: a,b", "tags[2]: b,a", 1),
+		strings.Replace(base, "port: 80", "port: \"80\"", 1),
+		strings.Replace(base, "port: 80", "port: 80.000000000000001", 1),
+	} {
+		got, err := toon.Fingerprint([]byte(doc))
+		if err != nil {
+			t.Fatalf("Fingerprint: %v", err)
+		}
+		if got == want {
+			t.Fatalf("fingerprint should change for:\n%s", doc)
+		}
+	}
+
+	big, err := toon.Fingerprint([]byte("n: 12345678901234567890"))
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	if next, _ := toon.Fingerprint([]byte("n: 12345678901234567891")); next == big {
+		t.Fatalf("fingerprint should tell apart integers beyond float64 precision")
+	}
+
+	if _, err := toon.Fingerprint([]byte("items[2]: 1")); err == nil {
+		t.Fatalf("expected decode error")
+	}
+}
@@ -1,6 +1,9 @@
 package toon_test
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/toon-format/toon-go"
@@ -37,3 +40,65 @@ func TestDecodeInvalidQuotedString(t *testing.T) {
 		t.Fatalf("expected quoted string error")
 	}
 }
+
+func TestDecodeEmptyDocument(t *testing.T) {
+	value, err := toon.DecodeString("  \n\n")
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if root, ok := value.(map[string]any); !ok || len(root) != 0 {
+		t.Fatalf("expected empty object, got %#v", value)
+	}
+
+	var target profile
+	err = toon.UnmarshalString("  \n\n", &target, toon.WithErrorOnEmpty(true))
+	if !errors.Is(err, toon.ErrEmptyDocument) {
+		t.Fatalf("expected ErrEmptyDocument, got %v", err)
+	}
+}
+
+func TestMarshalUnsupportedTypeError(t *testing.T) {
+	type holder struct {
+		Callback func() `toon:"callback"`
+	}
+	cases := []struct {
+		value any
+		want  reflect.Type
+	}{
+		{func() {}, reflect.TypeOf(func() {})},
+		{make(chan int), reflect.TypeOf(make(chan int))},
+		{holder{Callback: func() {}}, reflect.TypeOf(func() {})},
+		{map[int]string{1: "a"}, reflect.TypeOf(map[int]string{})},
+		{[]any{1, complex(1, 2)}, reflect.TypeOf(complex128(0))},
+	}
+	for _, tc := range cases {
+		_, err := toon.Marshal(tc.value)
+		var unsupported *toon.UnsupportedTypeError
+		if !errors.As(err, &unsupported) {
+			t.Fatalf("Marshal(%T): expected UnsupportedTypeError, got %v", tc.value, err)
+		}
+		if unsupported.Type != tc.want {
+			t.Fatalf("Marshal(%T): Type = %v, want %v", tc.value, unsupported.Type, tc.want)
+		}
+	}
+}
+
+func TestUnmarshalTypeMismatchNamesPlainTypes(t *testing.T) {
+	var target struct {
+		Name string `toon:"name"`
+		On   bool   `toon:"on"`
+		Main shape  `toon:"main"`
+	}
+	cases := map[string]string{
+		"main: 5":      "cannot assign float64 to toon_test.shape",
+		"name: 1":      "cannot assign float64 to string",
+		"on:\n  a: 1":  "cannot assign map[string]interface {} to bool",
+		"name[2]: a,b": "cannot assign []interface {} to string",
+	}
+	for doc, want := range cases {
+		err := toon.UnmarshalString(doc, &target)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("UnmarshalString(%q): expected %q, got %v", doc, want, err)
+		}
+	}
+}
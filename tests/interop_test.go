@@ -0,0 +1,118 @@
+package toon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+type jsonPoint struct {
+	x, y int
+}
+
+func (p jsonPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"y": p.y, "x": p.x})
+}
+
+func (p *jsonPoint) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.x, p.y = raw.X, raw.Y
+	return nil
+}
+
+type jsonShape struct {
+	Name   string      `toon:"name"`
+	Origin jsonPoint   `toon:"origin"`
+	Path   []jsonPoint `toon:"path"`
+}
+
+func TestJSONBridgeRoundTrip(t *testing.T) {
+	shape := jsonShape{
+		Name:   "line",
+		Origin: jsonPoint{x: 1, y: 2},
+		Path:   []jsonPoint{{x: 3, y: 4}, {x: 5, y: 6}},
+	}
+
+	doc, err := toon.MarshalString(shape, toon.WithJSONBridge(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"name: line",
+		"origin:",
+		"  x: 1",
+		"  y: 2",
+		"path[2]{x,y}:",
+		"  3,4",
+		"  5,6",
+	)
+
+	var decoded jsonShape
+	if err := toon.UnmarshalString(doc, &decoded, toon.WithDecoderJSONBridge(true)); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if decoded.Origin != shape.Origin || len(decoded.Path) != 2 || decoded.Path[1] != shape.Path[1] {
+		t.Fatalf("unexpected decoded shape: %#v", decoded)
+	}
+}
+
+func TestJSONBridgeDisabledByDefault(t *testing.T) {
+	doc, err := toon.MarshalString(jsonShape{Name: "dot", Origin: jsonPoint{x: 1, y: 2}})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if strings.Contains(doc, "x: 1") {
+		t.Fatalf("bridge applied without opt-in: %s", doc)
+	}
+}
+
+func TestURLValuesAndHTTPHeaderRoundTrip(t *testing.T) {
+	values := url.Values{"q": {"toon go"}, "tag": {"a", "b,c"}, "empty": {}}
+	doc, err := toon.MarshalString(values)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"empty[0]:",
+		"q[1]: toon go",
+		`tag[2]: a,"b,c"`,
+	)
+	var decodedValues url.Values
+	if err := toon.UnmarshalString(doc, &decodedValues); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(decodedValues, values) {
+		t.Fatalf("url.Values mismatch: %#v", decodedValues)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Add("Accept", "text/html")
+	header.Add("Accept", "*/*")
+	doc, err = toon.MarshalString(header)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		`Accept[2]: text/html,*/*`,
+		`"Content-Type"[1]: text/plain; charset=utf-8`,
+	)
+	var decoded map[string][]string
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(http.Header(decoded), header) {
+		t.Fatalf("http.Header mismatch: %#v", decoded)
+	}
+}
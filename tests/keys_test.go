@@ -0,0 +1,285 @@
+package toon_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+func TestDottedKeyExpansion(t *testing.T) {
+	doc := strings.Join([]string{
+		"server.http.port: 8080",
+		"server:",
+		"  name: api",
+		"\"a.b\": literal",
+		"tags.list[2]: x,y",
+	}, "\n")
+
+	literal := decodeMap(t, doc)
+	if literal["server.http.port"] != float64(8080) {
+		t.Fatalf("dotted key should stay literal by default: %#v", literal)
+	}
+
+	root := decodeMap(t, doc, toon.WithDottedKeyExpansion(true))
+	want := map[string]any{
+		"server": map[string]any{
+			"http": map[string]any{"port": float64(8080)},
+			"name": "api",
+		},
+		"a.b":  "literal",
+		"tags": map[string]any{"list": []any{"x", "y"}},
+	}
+	if !reflect.DeepEqual(root, want) {
+		t.Fatalf("unexpected expansion:\n got: %#v\nwant: %#v", root, want)
+	}
+
+	var obj toon.Object
+	if err := toon.UnmarshalString(doc, &obj, toon.WithDottedKeyExpansion(true)); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	server := obj.Fields[0].Value.(toon.Object)
+	if obj.Fields[0].Key != "server" || server.Fields[0].Key != "http" || server.Fields[1].Key != "name" {
+		t.Fatalf("unexpected ordered expansion: %#v", obj)
+	}
+}
+
+func TestDottedKeyExpansionConflicts(t *testing.T) {
+	doc := strings.Join([]string{
+		"a: 1",
+		"a.b: 2",
+	}, "\n")
+	if _, err := toon.DecodeString(doc, toon.WithDottedKeyExpansion(true)); err == nil {
+		t.Fatalf("expected strict conflict error")
+	}
+
+	root := decodeMap(t, doc, toon.WithDottedKeyExpansion(true), toon.WithStrictMode(false))
+	if !reflect.DeepEqual(root, map[string]any{"a": map[string]any{"b": float64(2)}}) {
+		t.Fatalf("later value should win in permissive mode: %#v", root)
+	}
+}
+
+func TestDottedKeyCollapse(t *testing.T) {
+	payload := toon.NewObject(
+		toon.Field{Key: "server", Value: toon.NewObject(
+			toon.Field{Key: "http", Value: toon.NewObject(
+				toon.Field{Key: "port", Value: 8080},
+			)},
+		)},
+		toon.Field{Key: "db", Value: toon.NewObject(
+			toon.Field{Key: "primary", Value: toon.NewObject(
+				toon.Field{Key: "host", Value: "a"},
+				toon.Field{Key: "port", Value: 5432},
+			)},
+		)},
+		toon.Field{Key: "tags", Value: toon.NewObject(
+			toon.Field{Key: "list", Value: []string{"x", "y"}},
+		)},
+		toon.Field{Key: "odd", Value: toon.NewObject(
+			toon.Field{Key: "needs quote", Value: true},
+		)},
+	)
+
+	doc, err := toon.MarshalString(payload, toon.WithDottedKeyCollapse(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"server.http.port: 8080",
+		"db.primary:",
+		"  host: a",
+		"  port: 5432",
+		"tags.list[2]: x,y",
+		"odd:",
+		"  \"needs quote\": true",
+	)
+
+	root := decodeMap(t, doc, toon.WithDottedKeyExpansion(true))
+	server := root["server"].(map[string]any)["http"].(map[string]any)
+	if server["port"] != float64(8080) {
+		t.Fatalf("collapsed key did not round-trip: %#v", root)
+	}
+}
+
+func TestDottedKeyCollapseQuotesLiteralDots(t *testing.T) {
+	payload := map[string]any{
+		"a.b": float64(1),
+		"c":   map[string]any{"d": map[string]any{"e.f": float64(2)}},
+		"items": []any{
+			map[string]any{"x": map[string]any{"y": float64(3)}, "z": float64(4)},
+			map[string]any{"k.v": float64(5), "m": map[string]any{"n": float64(6)}},
+		},
+	}
+
+	doc, err := toon.MarshalString(payload, toon.WithDottedKeyCollapse(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		`"a.b": 1`,
+		"c.d:",
+		`  "e.f": 2`,
+		"items[2]:",
+		"  - x.y: 3",
+		"    z: 4",
+		`  - "k.v": 5`,
+		"    m.n: 6",
+	)
+
+	decoded, err := toon.DecodeString(doc, toon.WithDottedKeyExpansion(true))
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("round trip mismatch: %#v", decoded)
+	}
+}
+
+func TestQuotedEmptyKeyRoundTrip(t *testing.T) {
+	payload := map[string]any{
+		"":       float64(1),
+		"nested": map[string]any{"": map[string]any{"x": float64(1)}},
+		"list":   []any{map[string]any{"": []any{float64(1)}, "b": float64(2)}},
+		"rows":   []any{map[string]any{"": float64(1), "a": float64(2)}},
+		"arr":    map[string]any{"": []any{float64(1), float64(2)}},
+	}
+
+	doc, err := toon.MarshalString(payload)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	lines := strings.Split(doc, "\n")
+	for _, want := range []string{`"": 1`, `  ""[2]: 1,2`, `  - ""[1]: 1`, `rows[1]{"",a}:`} {
+		if !containsLine(lines, want) {
+			t.Fatalf("expected line %q in:\n%s", want, doc)
+		}
+	}
+	decoded, err := toon.DecodeString(doc)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("round trip mismatch: %#v", decoded)
+	}
+
+	// A missing key is an error; only the quoted form denotes the empty key.
+	for _, doc := range []string{"a: 1\n: 2", "a: 1\n[2]: 1,2", "items[1]:\n  - [1]: 1\n    b: 2"} {
+		if _, err := toon.DecodeString(doc); err == nil {
+			t.Fatalf("expected error for missing key in %q", doc)
+		}
+	}
+}
+
+func TestObjectBuilder(t *testing.T) {
+	obj := toon.NewObject()
+	obj.Set("name", "prompt")
+	obj.Set("count", 1)
+	obj.SetObject("meta", func(o *toon.Object) {
+		o.Set("lang", "en")
+	})
+	obj.SetObject("meta", func(o *toon.Object) {
+		o.Set("tone", "terse")
+	})
+	obj.Set("name", "builder")
+	obj.Set("drop", true)
+	obj.Delete("drop")
+	obj.Delete("absent")
+
+	if !obj.Has("count") || obj.Has("drop") {
+		t.Fatalf("unexpected keys: %#v", obj.Fields)
+	}
+	if v, ok := obj.Get("name"); !ok || v != "builder" {
+		t.Fatalf("Get(name) = %#v, %v", v, ok)
+	}
+	if _, ok := obj.Get("drop"); ok {
+		t.Fatalf("deleted key still present")
+	}
+
+	doc, err := toon.MarshalString(obj)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"name: builder",
+		"count: 1",
+		"meta:",
+		"  lang: en",
+		"  tone: terse",
+	)
+}
+
+func TestNewObjectFromMap(t *testing.T) {
+	m := map[string]any{"zeta": 1, "id": 7, "alpha": 2, "name": "x"}
+	obj, err := toon.NewObjectFromMap(m, []string{"id", "name", "id"})
+	if err != nil {
+		t.Fatalf("NewObjectFromMap: %v", err)
+	}
+	doc, err := toon.MarshalString(obj)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"id: 7",
+		"name: x",
+		"alpha: 2",
+		"zeta: 1",
+	)
+
+	if _, err := toon.NewObjectFromMap(m, []string{"missing"}); err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+	obj, err = toon.NewObjectFromMap(map[string]any{"a": 1}, []string{"missing", "a"}, toon.WithMissingKeysAsNull(true))
+	if err != nil {
+		t.Fatalf("NewObjectFromMap: %v", err)
+	}
+	if v, ok := obj.Get("missing"); !ok || v != nil || obj.Fields[1].Key != "a" {
+		t.Fatalf("unexpected object: %#v", obj.Fields)
+	}
+}
+
+func TestQuotedKeysWithSpecialCharacters(t *testing.T) {
+	doc := strings.Join([]string{
+		`"a:b": 1`,
+		`"a:b[2]": 2`,
+		`"x]": 3`,
+		`"c,d"[2]: x,y`,
+		`"[k]":`,
+		`  "p|q": 4`,
+		`rows[2|]{"a|b"|"c:d"|"e[1]"|"f,g"}:`,
+		`  1|2|3|4`,
+		`  5|6|7|8`,
+	}, "\n")
+	want := map[string]any{
+		"a:b":    float64(1),
+		"a:b[2]": float64(2),
+		"x]":     float64(3),
+		"c,d":    []any{"x", "y"},
+		"[k]":    map[string]any{"p|q": float64(4)},
+		"rows": []any{
+			map[string]any{"a|b": float64(1), "c:d": float64(2), "e[1]": float64(3), "f,g": float64(4)},
+			map[string]any{"a|b": float64(5), "c:d": float64(6), "e[1]": float64(7), "f,g": float64(8)},
+		},
+	}
+	got, err := toon.DecodeString(doc)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected value: %#v", got)
+	}
+
+	for _, delimiter := range []toon.Delimiter{toon.DelimiterComma, toon.DelimiterPipe, toon.DelimiterTab} {
+		encoded, err := toon.MarshalString(want, toon.WithArrayDelimiter(delimiter))
+		if err != nil {
+			t.Fatalf("MarshalString: %v", err)
+		}
+		decoded, err := toon.DecodeString(encoded)
+		if err != nil {
+			t.Fatalf("DecodeString(%q): %v\n%s", delimiter, err, encoded)
+		}
+		if !reflect.DeepEqual(decoded, want) {
+			t.Fatalf("round trip with %q: %#v\n%s", delimiter, decoded, encoded)
+		}
+	}
+}
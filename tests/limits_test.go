@@ -0,0 +1,112 @@
+package toon_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+func TestDecodeMaxInputBytes(t *testing.T) {
+	doc := "name: Ada\nrole: admin"
+	if _, err := toon.DecodeString(doc, toon.WithMaxInputBytes(len(doc))); err != nil {
+		t.Fatalf("DecodeString at limit: %v", err)
+	}
+	_, err := toon.DecodeString(doc, toon.WithMaxInputBytes(16))
+	if err == nil || err.Error() != "toon: input exceeds 16 bytes" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeMaxLineBytes(t *testing.T) {
+	doc := "name: Ada\nbio: " + strings.Repeat("x", 64) + "\nrole: admin"
+	if _, err := toon.DecodeString(doc, toon.WithMaxLineBytes(69)); err != nil {
+		t.Fatalf("DecodeString at limit: %v", err)
+	}
+	_, err := toon.DecodeString(doc, toon.WithMaxLineBytes(32))
+	if err == nil || err.Error() != "line 2: line exceeds 32 bytes" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeMaxElements(t *testing.T) {
+	doc := strings.Join([]string{
+		"tags[3]: a,b,c",
+		"users[2]{id}:",
+		"  1",
+		"  2",
+		"events[1]:",
+		"  - ready",
+	}, "\n")
+	if _, err := toon.DecodeString(doc, toon.WithMaxElements(6)); err != nil {
+		t.Fatalf("DecodeString at limit: %v", err)
+	}
+	_, err := toon.DecodeString(doc, toon.WithMaxElements(5))
+	if err == nil || err.Error() != "line 6: array element limit of 5 exceeded" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = toon.DecodeString("items[1000000000]: 1,2,3", toon.WithStrictMode(false), toon.WithMaxElements(2))
+	if err == nil {
+		t.Fatalf("expected element limit error for oversized inline array")
+	}
+}
+
+func TestDecodeDeclaredLengthDoesNotDriveAllocation(t *testing.T) {
+	docs := []string{
+		"items[2000000000]:",
+		"rows[2000000000]{id,name}:",
+	}
+	for _, doc := range docs {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if _, err := toon.DecodeString(doc); err == nil {
+			t.Fatalf("expected length mismatch for %q", doc)
+		}
+		runtime.ReadMemStats(&after)
+		if grown := after.TotalAlloc - before.TotalAlloc; grown > 1<<20 {
+			t.Fatalf("decoding %q allocated %d bytes", doc, grown)
+		}
+	}
+}
+
+func TestDecodeForbiddenKeys(t *testing.T) {
+	forbid := toon.WithForbiddenKeys("__proto__", "internal")
+	rejected := []string{
+		"__proto__: 1",
+		"a:\n  b:\n    internal: true",
+		"items[1]:\n  - id: 1\n    internal: x",
+		"items[1]:\n  - internal:\n      a: 1",
+		"rows[1]{id,__proto__}:\n  1,2",
+		"internal[2]: 1,2",
+		`"__proto__": 1`,
+	}
+	for _, doc := range rejected {
+		if _, err := toon.DecodeString(doc, forbid); err == nil || !strings.Contains(err.Error(), "forbidden key") {
+			t.Fatalf("%q: expected forbidden key error, got %v", doc, err)
+		}
+		var v map[string]any
+		if err := toon.UnmarshalString(doc, &v, forbid); err == nil {
+			t.Fatalf("%q: expected Unmarshal to fail", doc)
+		}
+	}
+
+	if _, err := toon.DecodeString("a.__proto__.b: 1", forbid, toon.WithDottedKeyExpansion(true)); err == nil {
+		t.Fatalf("expected dotted segment to be checked")
+	}
+	if _, err := toon.DecodeString("a.__proto__.b: 1", forbid); err != nil {
+		t.Fatalf("unexpanded dotted key should be allowed: %v", err)
+	}
+	if _, err := toon.DecodeString("name: internal\ntags[1]: __proto__", forbid); err != nil {
+		t.Fatalf("values must not be checked: %v", err)
+	}
+
+	it := toon.NewDecoder(forbid).Elements([]byte("[1]{id,internal}:\n  1,2"))
+	for range it.All() {
+		t.Fatalf("no element should be produced")
+	}
+	if it.Err() == nil {
+		t.Fatalf("expected iterator error")
+	}
+}
@@ -0,0 +1,49 @@
+package toon_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+func TestLint(t *testing.T) {
+	if issues := toon.Lint([]byte("name: Ada\ntags[2]: a,b")); issues != nil {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+
+	doc := strings.Join([]string{
+		"name: Ada",
+		"tags[3]: a,b",
+		"users[2]{id,name}:",
+		"  1,Ada,extra",
+		"   2,Bob",
+		"name: Grace",
+		"bad[x]: 1",
+		"  nested: true",
+		"items[1]:",
+		"  - one",
+		"  - two",
+	}, "\n")
+	want := []toon.SyntaxError{
+		{Line: 2, Msg: "inline array length mismatch; expected 3, got 2"},
+		{Line: 4, Msg: "tabular row width mismatch"},
+		{Line: 5, Msg: "indentation must be a multiple of 2 spaces"},
+		{Line: 6, Msg: `duplicate key "name"`},
+		{Line: 7, Msg: "invalid delimiter symbol 'x'"},
+		{Line: 11, Msg: "list length mismatch; expected 1 items"},
+	}
+	if got := toon.Lint([]byte(doc)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected issues:\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestDecodeSyntaxError(t *testing.T) {
+	_, err := toon.DecodeString("a: 1\n   b: 2")
+	var syntaxErr toon.SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Line != 2 {
+		t.Fatalf("expected SyntaxError at line 2, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package toon_test
+
+import (
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+func TestMerge(t *testing.T) {
+	base := "name: app\nserver:\n  host: localhost\n  port: 80\ntags[2]: a,b\nlimits:\n  cpu: 1\n"
+	override := "server:\n  port: 8080\n  tls: true\ntags[1]: c\nlimits: none\nextra: null\n"
+
+	cases := []struct {
+		name string
+		opts []toon.MergeOption
+		want string
+	}{
+		{
+			name: "replace",
+			want: "name: app\nserver:\n  host: localhost\n  port: 8080\n  tls: true\ntags[1]: c\nlimits: none\nextra: null",
+		},
+		{
+			name: "append",
+			opts: []toon.MergeOption{toon.WithArrayMerge(toon.ArrayMergeAppend)},
+			want: "name: app\nserver:\n  host: localhost\n  port: 8080\n  tls: true\ntags[3]: a,b,c\nlimits: none\nextra: null",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := toon.Merge([]byte(base), []byte(override), tc.opts...)
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("Merge mismatch:\nwant:\n%s\n\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestMergeDisjointKeysAndRootScalar(t *testing.T) {
+	got, err := toon.Merge([]byte("a: 1"), []byte("b: 2"),
+		toon.WithMergeEncoderOptions(toon.WithIndent(4)))
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if string(got) != "a: 1\nb: 2" {
+		t.Fatalf("unexpected merge: %q", got)
+	}
+
+	got, err = toon.Merge([]byte("a:\n  b: 1"), []byte("7"))
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if string(got) != "7" {
+		t.Fatalf("root scalar should replace object, got %q", got)
+	}
+}
+
+func TestMergeInvalidInput(t *testing.T) {
+	if _, err := toon.Merge([]byte("a: 1"), []byte("items[2]: 1")); err == nil {
+		t.Fatalf("expected error for invalid override")
+	}
+}
+
+func TestMergeKeepsUntouchedNumbers(t *testing.T) {
+	base := "a: 9007199254740993\nb: 1.10\nc: 1e3\nname: x"
+	got, err := toon.Merge([]byte(base), []byte("name: y\nd: 0.50"))
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	want := "a: 9007199254740993\nb: 1.10\nc: 1e3\nname: y\nd: 0.50"
+	if string(got) != want {
+		t.Fatalf("Merge mismatch:\nwant:\n%s\n\ngot:\n%s", want, got)
+	}
+}
@@ -1,7 +1,13 @@
 package toon_test
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/toon-format/toon-go"
@@ -64,3 +70,678 @@ func TestTimeFormatterOptionDoesNotLeak(t *testing.T) {
 		t.Fatalf("time formatter leaked into default encoder")
 	}
 }
+
+func TestProfiles(t *testing.T) {
+	payload := map[string]any{
+		"meta": map[string]any{"owner": map[string]any{"name": "Ada"}},
+		"tags": []any{"a,b", "c"},
+	}
+	cases := []struct {
+		profile toon.Profile
+		want    string
+	}{
+		{toon.ProfileStrict, "meta:\n  owner:\n    name: Ada\ntags[#2]: \"a,b\",c"},
+		{toon.ProfileCompact, "meta:\n  owner:\n    name: Ada\ntags[2\t]: a,b\tc"},
+		{toon.ProfileVerbose, "meta:\n    owner:\n        name: Ada\ntags[#2]: \"a,b\",c"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.profile.String(), func(t *testing.T) {
+			doc, err := toon.MarshalString(payload, toon.WithProfile(tc.profile))
+			if err != nil {
+				t.Fatalf("MarshalString: %v", err)
+			}
+			if doc != tc.want {
+				t.Fatalf("unexpected doc:\n%s\nwant:\n%s", doc, tc.want)
+			}
+			decoded, err := toon.DecodeString(doc, toon.WithDecoderProfile(tc.profile))
+			if err != nil {
+				t.Fatalf("DecodeString: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, payload) {
+				t.Fatalf("round trip mismatch: %#v", decoded)
+			}
+
+			type item struct {
+				A int `toon:"a"`
+			}
+			items := []*item{{A: 1}, nil, {A: 2}}
+			doc, err = toon.MarshalString(items, toon.WithProfile(tc.profile))
+			if err != nil {
+				t.Fatalf("MarshalString: %v", err)
+			}
+			var back []*item
+			if err := toon.UnmarshalString(doc, &back, toon.WithDecoderProfile(tc.profile)); err != nil {
+				t.Fatalf("UnmarshalString(%q): %v", doc, err)
+			}
+			if !reflect.DeepEqual(back, items) {
+				t.Fatalf("pointer slice round trip mismatch: %#v", back)
+			}
+		})
+	}
+}
+
+func TestProfileStrictDecoder(t *testing.T) {
+	var target struct {
+		ID int `toon:"id"`
+	}
+	for doc, want := range map[string]string{
+		"":                 "empty",
+		"id: 1\nname: Ada": "unknown field",
+		"id: 1.5":          "id",
+	} {
+		err := toon.UnmarshalString(doc, &target, toon.WithDecoderProfile(toon.ProfileStrict))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("UnmarshalString(%q): expected error mentioning %q, got %v", doc, want, err)
+		}
+	}
+}
+
+func TestTabularThreshold(t *testing.T) {
+	rows := []map[string]any{
+		{"id": 1, "name": "Ada"},
+		{"id": 2, "name": "Bob"},
+		{"id": 3},
+	}
+	doc, err := toon.MarshalString(rows, toon.WithTabularThreshold(0.5))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if want := "[3]{id,name}:\n  1,Ada\n  2,Bob\n  3,null"; doc != want {
+		t.Fatalf("unexpected doc:\n%s\nwant:\n%s", doc, want)
+	}
+	doc, err = toon.MarshalString(rows, toon.WithTabularThreshold(0.9))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if strings.Contains(doc, "{") {
+		t.Fatalf("expected list form below the threshold:\n%s", doc)
+	}
+}
+
+func TestAutoDelimiter(t *testing.T) {
+	payload := map[string]any{
+		"plain": []any{"a", "b"},
+		"rows":  []map[string]any{{"note": "x|y, z"}, {"note": "w, v"}},
+	}
+	doc, err := toon.MarshalString(payload, toon.WithAutoDelimiter(true), toon.WithArrayDelimiter(toon.DelimiterPipe))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if want := "plain[2|]: a|b\nrows[2\t]{note}:\n  x|y, z\n  w, v"; doc != want {
+		t.Fatalf("unexpected doc:\n%q\nwant:\n%q", doc, want)
+	}
+	decoded, err := toon.DecodeString(doc)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if got := decoded.(map[string]any)["rows"].([]any)[0].(map[string]any)["note"]; got != "x|y, z" {
+		t.Fatalf("round trip mismatch: %#v", got)
+	}
+}
+
+func TestProfileOverriddenByLaterOption(t *testing.T) {
+	doc, err := toon.MarshalString(map[string]any{"tags": []any{"a"}},
+		toon.WithProfile(toon.ProfileStrict),
+		toon.WithLengthMarkers(false),
+	)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "tags[1]: a" {
+		t.Fatalf("unexpected doc: %s", doc)
+	}
+}
+
+func TestNaturalKeyOrder(t *testing.T) {
+	payload := map[string]int{"item10": 10, "item2": 2, "item1": 1, "v1.10": 0, "v1.9": 0, "b": 0, "a02": 0, "a2": 0}
+
+	doc, err := toon.MarshalString(payload, toon.WithNaturalKeyOrder(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	want := "a02: 0\na2: 0\nb: 0\nitem1: 1\nitem2: 2\nitem10: 10\nv1.9: 0\nv1.10: 0"
+	if doc != want {
+		t.Fatalf("unexpected doc:\n%s\nwant:\n%s", doc, want)
+	}
+
+	doc, err = toon.MarshalString(payload)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if !strings.HasPrefix(doc, "a02: 0\na2: 0\nb: 0\nitem1: 1\nitem10: 10\n") {
+		t.Fatalf("default order should stay lexicographic:\n%s", doc)
+	}
+}
+
+func TestEmitEmptyObject(t *testing.T) {
+	type empty struct{}
+
+	doc, err := toon.MarshalString(empty{})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "" {
+		t.Fatalf("default empty root should be an empty document, got %q", doc)
+	}
+
+	doc, err = toon.MarshalString(empty{}, toon.WithEmitEmptyObject(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "{}" {
+		t.Fatalf("unexpected doc: %q", doc)
+	}
+	decoded, err := toon.DecodeString(doc, toon.WithErrorOnEmpty(true))
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if m, ok := decoded.(map[string]any); !ok || len(m) != 0 {
+		t.Fatalf("expected empty map, got %#v", decoded)
+	}
+
+	doc, err = toon.MarshalString(map[string]any{"inner": map[string]any{}}, toon.WithEmitEmptyObject(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "inner:" {
+		t.Fatalf("nested empty objects keep their form, got %q", doc)
+	}
+
+	doc, err = toon.MarshalString("{}")
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if decoded, err := toon.DecodeString(doc); err != nil || decoded != "{}" {
+		t.Fatalf("string \"{}\" should round trip, got %#v (%v)", decoded, err)
+	}
+}
+
+func TestOptionsStruct(t *testing.T) {
+	payload := map[string]any{"tags": []any{"a", "b"}, "meta": map[string]any{"id": float64(1)}}
+
+	doc, err := toon.MarshalWith(payload, toon.Options{Indent: 4, Delimiter: toon.DelimiterPipe, LengthMarkers: true})
+	if err != nil {
+		t.Fatalf("MarshalWith: %v", err)
+	}
+	want, err := toon.Marshal(payload,
+		toon.WithIndent(4),
+		toon.WithArrayDelimiter(toon.DelimiterPipe),
+		toon.WithLengthMarkers(true),
+	)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(doc) != string(want) {
+		t.Fatalf("MarshalWith diverged from functional options:\n%s\nwant:\n%s", doc, want)
+	}
+
+	defaults, err := toon.MarshalWith(payload, toon.Options{})
+	if err != nil {
+		t.Fatalf("MarshalWith: %v", err)
+	}
+	plain, err := toon.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(defaults) != string(plain) {
+		t.Fatalf("zero Options should match defaults:\n%s\nwant:\n%s", defaults, plain)
+	}
+
+	decoded, err := toon.DecodeWith(doc, toon.DecoderOptions{Indent: 4})
+	if err != nil {
+		t.Fatalf("DecodeWith: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("round trip mismatch: %#v", decoded)
+	}
+	if _, err := toon.DecodeWith(doc, toon.DecoderOptions{}); err == nil {
+		t.Fatalf("expected strict indentation error with default decoder options")
+	}
+	if _, err := toon.DecodeWith([]byte("a:\n   b: 1"), toon.DecoderOptions{Lenient: true}); err != nil {
+		t.Fatalf("Lenient should disable strict mode: %v", err)
+	}
+
+	var target struct {
+		Tags []string `toon:"tags"`
+	}
+	if err := toon.UnmarshalWith(doc, &target, toon.DecoderOptions{Indent: 4, DisallowUnknownFields: true}); err == nil {
+		t.Fatalf("expected unknown field error")
+	}
+}
+
+func TestAppendMarshal(t *testing.T) {
+	enc := toon.NewEncoder(toon.WithLengthMarkers(true))
+	payloads := []any{
+		usersPayload{Users: []profile{{ID: 1, Name: "Ada", Active: true}}, Count: 1},
+		[]any{1, "two"},
+		"scalar",
+		map[string]any{},
+	}
+
+	buf := make([]byte, 0, 64)
+	for _, payload := range payloads {
+		want, err := enc.Marshal(payload)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		buf, err = enc.AppendMarshal(buf[:0], payload)
+		if err != nil {
+			t.Fatalf("AppendMarshal: %v", err)
+		}
+		if string(buf) != string(want) {
+			t.Fatalf("AppendMarshal = %q, want %q", buf, want)
+		}
+	}
+
+	out, err := enc.AppendMarshal([]byte("prefix\n"), []any{1})
+	if err != nil {
+		t.Fatalf("AppendMarshal: %v", err)
+	}
+	if string(out) != "prefix\n[#1]: 1" {
+		t.Fatalf("unexpected appended output %q", out)
+	}
+
+	out, err = enc.AppendMarshal([]byte("keep"), make(chan int))
+	if err == nil || string(out) != "keep" {
+		t.Fatalf("expected error with dst unchanged, got %q (%v)", out, err)
+	}
+}
+
+func TestMapKeyComparator(t *testing.T) {
+	priority := map[string]int{"name": 0, "version": 1}
+	rank := func(key string) int {
+		if r, ok := priority[key]; ok {
+			return r
+		}
+		return len(priority)
+	}
+	compare := func(a, b string) int { return rank(a) - rank(b) }
+
+	payload := map[string]int{"zeta": 1, "version": 2, "alpha": 3, "name": 4, "beta": 5}
+	want := "name: 4\nversion: 2\nalpha: 3\nbeta: 5\nzeta: 1"
+	for i := 0; i < 5; i++ {
+		doc, err := toon.MarshalString(payload, toon.WithMapKeyComparator(compare))
+		if err != nil {
+			t.Fatalf("MarshalString: %v", err)
+		}
+		if doc != want {
+			t.Fatalf("unexpected doc:\n%s\nwant:\n%s", doc, want)
+		}
+	}
+
+	doc, err := toon.MarshalString(map[string]int{"b": 1, "a": 2},
+		toon.WithNaturalKeyOrder(true),
+		toon.WithMapKeyComparator(func(a, b string) int { return strings.Compare(b, a) }),
+	)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "b: 1\na: 2" {
+		t.Fatalf("comparator should take precedence: %q", doc)
+	}
+
+	doc, err = toon.MarshalString(map[string]int{"b": 1, "a": 2}, toon.WithMapKeyComparator(nil))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "a: 2\nb: 1" {
+		t.Fatalf("nil comparator should keep default order: %q", doc)
+	}
+}
+
+func TestMaxInlineWidth(t *testing.T) {
+	payload := toon.NewObject(
+		toon.Field{Key: "short", Value: []int{1, 2}},
+		toon.Field{Key: "long", Value: []string{"alpha", "bravo", "charlie"}},
+		toon.Field{Key: "rows", Value: []toon.Object{
+			toon.NewObject(toon.Field{Key: "id", Value: 1}, toon.Field{Key: "name", Value: "Ada"}),
+		}},
+		toon.Field{Key: "wide", Value: []toon.Object{
+			toon.NewObject(toon.Field{Key: "id", Value: 2}, toon.Field{Key: "name", Value: "a rather long name"}),
+		}},
+	)
+
+	doc, err := toon.MarshalString(payload, toon.WithMaxInlineWidth(16))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"short[2]: 1,2",
+		"long[3]:",
+		"  - alpha",
+		"  - bravo",
+		"  - charlie",
+		"rows[1]{id,name}:",
+		"  1,Ada",
+		"wide[1]:",
+		"  - id: 2",
+		"    name: a rather long name",
+	)
+
+	unlimited, err := toon.MarshalString(payload, toon.WithMaxInlineWidth(0))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	plain, err := toon.MarshalString(payload)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if unlimited != plain {
+		t.Fatalf("zero width should disable the limit:\n%s", unlimited)
+	}
+
+	wrapped := decodeMap(t, doc)
+	unwrapped := decodeMap(t, plain)
+	if !reflect.DeepEqual(wrapped, unwrapped) {
+		t.Fatalf("wrapped document decodes differently:\n%#v\n%#v", wrapped, unwrapped)
+	}
+}
+
+func TestWriterEncoder(t *testing.T) {
+	var out strings.Builder
+	enc := toon.NewWriterEncoder(&out, toon.WithLengthMarkers(true))
+	enc.SetIndent(4)
+	enc.SetDelimiter(toon.DelimiterPipe)
+
+	if err := enc.Encode(map[string]any{"user": map[string]any{"tags": []string{"a", "b"}}}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	enc.SetIndent(0)
+	enc.SetDelimiter(toon.DelimiterComma)
+	if err := enc.Encode([]int{1, 2}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Encode(func() {}); err == nil {
+		t.Fatalf("expected unsupported type error")
+	}
+
+	if err := enc.Encode("---"); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Encode(map[string]any{}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	want := "user:\n    tags[#2|]: a|b\n---\n[#2]: 1,2\n---\n\"---\"\n---\n\n"
+	if out.String() != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", out.String(), want)
+	}
+
+	dec := toon.NewReaderDecoder(strings.NewReader(out.String()), toon.WithDecoderIndent(4))
+	var docs []any
+	for {
+		doc, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		docs = append(docs, doc)
+	}
+	wantDocs := []any{
+		map[string]any{"user": map[string]any{"tags": []any{"a", "b"}}},
+		[]any{float64(1), float64(2)},
+		"---",
+		map[string]any{},
+	}
+	if !reflect.DeepEqual(docs, wantDocs) {
+		t.Fatalf("unexpected documents: %#v", docs)
+	}
+}
+
+func TestReaderDecoderLimits(t *testing.T) {
+	dec := toon.NewReaderDecoder(strings.NewReader("a: 1\n---\nb: "+strings.Repeat("x", 64)+"\n---\nc: 3"), toon.WithMaxInputBytes(16))
+	var first struct {
+		A int `toon:"a"`
+	}
+	if err := dec.Unmarshal(&first); err != nil || first.A != 1 {
+		t.Fatalf("Unmarshal: %+v (%v)", first, err)
+	}
+	if _, err := dec.Decode(); err == nil || err.Error() != "toon: input exceeds 16 bytes" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A long line is abandoned once it passes the limit rather than read whole.
+	long := io.MultiReader(strings.NewReader("a: "+strings.Repeat("x", 8192)), iotest.ErrReader(errors.New("read past the limit")))
+	if _, err := toon.NewReaderDecoder(long, toon.WithMaxInputBytes(16)).Decode(); err == nil || err.Error() != "toon: input exceeds 16 bytes" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := toon.NewReaderDecoder(strings.NewReader("")).Decode(); err != io.EOF {
+		t.Fatalf("expected io.EOF for an empty stream, got %v", err)
+	}
+}
+
+func TestGzipEncoderDecoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := toon.NewGzipEncoder(&buf, toon.WithLengthMarkers(true))
+	payload := map[string]any{"users": []map[string]any{{"id": 1, "name": "Ada"}, {"id": 2, "name": "Bob"}}}
+	if err := enc.Encode(payload); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if err := enc.Encode([]int{7}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	// Until Close, the stream is incomplete and cannot be decoded.
+	if dec, err := toon.NewGzipDecoder(bytes.NewReader(buf.Bytes())); err == nil {
+		if _, err := dec.Decode(); err == nil {
+			t.Fatalf("expected truncated stream before Close")
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	dec, err := toon.NewGzipDecoder(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewGzipDecoder: %v", err)
+	}
+	defer dec.Close()
+	var out struct {
+		Users []struct {
+			ID   int    `toon:"id"`
+			Name string `toon:"name"`
+		} `toon:"users"`
+	}
+	if err := dec.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(out.Users) != 2 || out.Users[1].Name != "Bob" {
+		t.Fatalf("unexpected result: %+v", out)
+	}
+	if second, err := dec.Decode(); err != nil || !reflect.DeepEqual(second, []any{float64(7)}) {
+		t.Fatalf("second document: %#v (%v)", second, err)
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Fatalf("expected io.EOF after the last document, got %v", err)
+	}
+
+	dec, err = toon.NewGzipDecoder(bytes.NewReader(buf.Bytes()), toon.WithMaxInputBytes(8))
+	if err != nil {
+		t.Fatalf("NewGzipDecoder: %v", err)
+	}
+	if _, err := dec.Decode(); err == nil || err.Error() != "toon: input exceeds 8 bytes" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := toon.NewGzipDecoder(strings.NewReader("users[0]:")); err == nil {
+		t.Fatalf("expected gzip header error")
+	}
+}
+
+func TestLengthMarkersFunc(t *testing.T) {
+	payload := toon.NewObject(
+		toon.Field{Key: "tags", Value: []string{"a", "b"}},
+		toon.Field{Key: "nested", Value: toon.NewObject(
+			toon.Field{Key: "ids", Value: []int{1, 2, 3}},
+		)},
+		toon.Field{Key: "groups", Value: []any{[]int{4, 5}}},
+	)
+	topLevel := func(depth int) bool { return depth == 0 }
+
+	doc, err := toon.MarshalString(payload, toon.WithLengthMarkersFunc(topLevel))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"tags[#2]: a,b",
+		"nested:",
+		"  ids[3]: 1,2,3",
+		"groups[#1]:",
+		"  - [2]: 4,5",
+	)
+
+	doc, err = toon.MarshalString(payload, toon.WithLengthMarkersFunc(topLevel), toon.WithLengthMarkers(false))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if strings.Contains(doc, "#") {
+		t.Fatalf("WithLengthMarkers should replace the predicate:\n%s", doc)
+	}
+
+	doc, err = toon.MarshalString(payload, toon.WithLengthMarkers(true), toon.WithLengthMarkersFunc(nil))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if strings.Count(doc, "#") != 4 {
+		t.Fatalf("nil predicate should fall back to WithLengthMarkers:\n%s", doc)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	type record struct {
+		Zeta  int       `toon:"zeta"`
+		Alpha time.Time `toon:"alpha"`
+	}
+	zone := time.FixedZone("X", 3*60*60)
+	value := map[string]any{
+		"item10": record{Zeta: 1, Alpha: time.Date(2025, 1, 2, 3, 4, 5, 0, zone)},
+		"item2":  toon.NewObject(toon.Field{Key: "b", Value: 1}, toon.Field{Key: "a", Value: 2}),
+	}
+	opts := []toon.EncoderOption{
+		toon.WithNaturalKeyOrder(true),
+		toon.WithTimeLocation(zone),
+		toon.WithTimeFormatter(func(t time.Time) string { return t.Format(time.Kitchen) }),
+		toon.WithDeterministic(true),
+	}
+
+	doc, err := toon.MarshalString(value, opts...)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"item10:",
+		`  alpha: "2025-01-02T00:04:05Z"`,
+		"  zeta: 1",
+		"item2:",
+		"  a: 2",
+		"  b: 1",
+	)
+	again, err := toon.MarshalString(value, opts...)
+	if err != nil || again != doc {
+		t.Fatalf("output changed between runs:\n%s\n%s (%v)", doc, again, err)
+	}
+}
+
+func TestSchemaHeader(t *testing.T) {
+	value := map[string]any{"users": []map[string]any{{"id": 1, "name": "Ada"}}}
+	doc, err := toon.MarshalString(value, toon.WithSchemaHeader("users: list of {id, name}\n\nids are unique"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"# users: list of {id, name}",
+		"#",
+		"# ids are unique",
+		"users[1]{id,name}:",
+		"  1,Ada",
+	)
+
+	if _, err := toon.DecodeString(doc); err == nil {
+		t.Fatalf("expected the header to be rejected without WithSkipSchemaHeader")
+	}
+	decoded, err := toon.DecodeString(doc, toon.WithSkipSchemaHeader(true))
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	want := map[string]any{"users": []any{map[string]any{"id": float64(1), "name": "Ada"}}}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("unexpected value: %#v", decoded)
+	}
+
+	_, err = toon.DecodeString("# note\na: 1\n# not a comment", toon.WithSkipSchemaHeader(true))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected error on line 3, got %v", err)
+	}
+
+	// A root string starting with "#" must not be mistaken for the header.
+	doc, err = toon.MarshalString("#x", toon.WithSchemaHeader("h"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc, "# h", `"#x"`)
+	if decoded, err := toon.DecodeString(doc, toon.WithSkipSchemaHeader(true)); err != nil || decoded != "#x" {
+		t.Fatalf("root string: got %#v (%v)", decoded, err)
+	}
+}
+
+func TestFieldFilter(t *testing.T) {
+	type contact struct {
+		Email string `toon:"email"`
+		Phone string `toon:"phone"`
+	}
+	type account struct {
+		Name    string            `toon:"name"`
+		Contact contact           `toon:"contact"`
+		Users   []contact         `toon:"users"`
+		Labels  map[string]string `toon:"labels"`
+	}
+	value := account{
+		Name:    "acme",
+		Contact: contact{Email: "ops@acme.test", Phone: "555"},
+		Users:   []contact{{Email: "a@acme.test", Phone: "1"}},
+		Labels:  map[string]string{"tier": "gold"},
+	}
+
+	var seen []string
+	redact := func(path string) bool {
+		seen = append(seen, path)
+		return !strings.HasSuffix(path, ".phone") && path != "labels.tier"
+	}
+	doc, err := toon.MarshalString(value, toon.WithFieldFilter(redact))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"name: acme",
+		"contact:",
+		"  email: ops@acme.test",
+		"users[1]{email}:",
+		"  a@acme.test",
+	)
+	want := []string{"name", "contact", "contact.email", "contact.phone", "users", "users[0].email", "users[0].phone", "labels", "labels.tier"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Fatalf("unexpected paths: %q", seen)
+	}
+
+	// Objects emptied by the filter are dropped from their parent, while
+	// array elements keep their place.
+	doc, err = toon.MarshalString(value, toon.WithFieldFilter(func(path string) bool {
+		return path == "contact" || path == "users" || path == "contact.email"
+	}))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"contact:",
+		"  email: ops@acme.test",
+		"users[1]:",
+		"  - {}",
+	)
+	doc, err = toon.MarshalString(value, toon.WithFieldFilter(func(path string) bool {
+		return path == "name" || path == "contact"
+	}))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc, "name: acme")
+}
@@ -1,9 +1,11 @@
 package toon_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -85,6 +87,160 @@ func TestMarshalLargeIntegerPrecision(t *testing.T) {
 	}
 }
 
+func TestUnmarshalIntegerPrecision(t *testing.T) {
+	type record struct {
+		ID    int64  `toon:"id"`
+		Count uint64 `toon:"count"`
+	}
+
+	var got record
+	if err := toon.Unmarshal([]byte("id: 9007199254740993\ncount: 18446744073709551615"), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.ID != 9007199254740993 || got.Count != math.MaxUint64 {
+		t.Fatalf("unexpected record %+v", got)
+	}
+
+	want := record{ID: math.MinInt64, Count: math.MaxUint64}
+	doc, err := toon.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	got = record{}
+	if err := toon.Unmarshal(doc, &got); err != nil {
+		t.Fatalf("Unmarshal round trip: %v", err)
+	}
+	if got != want {
+		t.Fatalf("round trip mismatch: got %+v want %+v", got, want)
+	}
+
+	var small struct {
+		N int `toon:"n"`
+	}
+	if err := toon.Unmarshal([]byte("n: 1e3"), &small); err != nil || small.N != 1000 {
+		t.Fatalf("expected exponent integer, got %d (%v)", small.N, err)
+	}
+
+	for _, doc := range []string{"id: 1e20", "id: 1.5", "id: \"12\"", "count: -1"} {
+		var target record
+		if err := toon.Unmarshal([]byte(doc), &target); err == nil {
+			t.Fatalf("expected error for %q, got %+v", doc, target)
+		}
+	}
+
+	// Exponents too large to evaluate exactly must not fall back to float64.
+	for doc, msg := range map[string]string{
+		"id: 1e-9999999": "cannot assign non-integer 1e-9999999",
+		"id: 1.5e-400":   "cannot assign non-integer",
+	} {
+		var target record
+		if err := toon.Unmarshal([]byte(doc), &target); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("Unmarshal(%q): expected %q, got %v (%+v)", doc, msg, err, target)
+		}
+	}
+}
+
+func TestUnmarshalStrictIntegerSources(t *testing.T) {
+	type record struct {
+		ID    int64   `toon:"id"`
+		Count uint    `toon:"count"`
+		Score float64 `toon:"score"`
+	}
+
+	var got record
+	if err := toon.UnmarshalString("id: 42\ncount: 7\nscore: 1.0", &got, toon.WithStrictIntegerSources(true)); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if got != (record{ID: 42, Count: 7, Score: 1}) {
+		t.Fatalf("unexpected record %+v", got)
+	}
+
+	for _, doc := range []string{"id: 42.0", "count: 1e3"} {
+		if err := toon.UnmarshalString(doc, &got); err != nil {
+			t.Fatalf("%q should decode by default: %v", doc, err)
+		}
+		err := toon.UnmarshalString(doc, &got, toon.WithStrictIntegerSources(true))
+		if err == nil || !strings.Contains(err.Error(), "float literal") {
+			t.Fatalf("expected float literal error for %q, got %v", doc, err)
+		}
+	}
+}
+
+type bigID int64
+
+func TestMarshalNamedIntegerPrecision(t *testing.T) {
+	type record struct {
+		ID    bigID `toon:"id"`
+		Small bigID `toon:"small"`
+	}
+	want := record{ID: 9007199254740993, Small: 42}
+
+	doc, err := toon.MarshalString(want)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		`id: "9007199254740993"`,
+		"small: 42",
+	)
+
+	var got record
+	if err := toon.UnmarshalString(doc, &got); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if got != want {
+		t.Fatalf("round trip mismatch: got %+v want %+v", got, want)
+	}
+}
+
+type (
+	userID  int64
+	counter uint64
+	level   int8
+)
+
+func TestMarshalNamedIntegerBoundaries(t *testing.T) {
+	cases := []struct {
+		value any
+		want  string
+	}{
+		{userID(9007199254740991), "9007199254740991"},
+		{userID(9007199254740992), `"9007199254740992"`},
+		{userID(-9007199254740991), "-9007199254740991"},
+		{userID(-9007199254740992), `"-9007199254740992"`},
+		{counter(9007199254740991), "9007199254740991"},
+		{counter(math.MaxUint64), `"18446744073709551615"`},
+		{level(-128), "-128"},
+	}
+	for _, tc := range cases {
+		doc, err := toon.MarshalString(map[string]any{"v": tc.value})
+		if err != nil {
+			t.Fatalf("MarshalString(%T): %v", tc.value, err)
+		}
+		if doc != "v: "+tc.want {
+			t.Fatalf("MarshalString(%T(%v)) = %q, want %q", tc.value, tc.value, doc, "v: "+tc.want)
+		}
+	}
+
+	ids := []userID{1, 9007199254740993}
+	doc, err := toon.MarshalString(map[string]any{"ids": ids})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != `ids[2]: 1,"9007199254740993"` {
+		t.Fatalf("unexpected inline array: %q", doc)
+	}
+	var decoded struct {
+		IDs []userID `toon:"ids"`
+	}
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if len(decoded.IDs) != 2 || decoded.IDs[1] != ids[1] {
+		t.Fatalf("unexpected ids: %v", decoded.IDs)
+	}
+}
+
 func TestMarshalWithObjectHelper(t *testing.T) {
 	doc, err := toon.MarshalString(toon.NewObject(
 		toon.Field{Key: "first", Value: 1},
@@ -113,6 +269,35 @@ func TestMarshalCustomTimeFormatter(t *testing.T) {
 	}
 }
 
+func TestMarshalTimeLocation(t *testing.T) {
+	ts := time.Date(2025, 10, 31, 12, 0, 0, 0, time.UTC)
+	ny := time.FixedZone("EDT", -4*60*60)
+
+	doc, err := toon.MarshalString(map[string]any{"ts": ts}, toon.WithTimeLocation(ny))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc, "ts: \"2025-10-31T08:00:00-04:00\"")
+
+	var decoded struct {
+		TS time.Time `toon:"ts"`
+	}
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !decoded.TS.Equal(ts) {
+		t.Fatalf("instant changed: got %v want %v", decoded.TS, ts)
+	}
+
+	doc, err = toon.MarshalString(map[string]any{"ts": ts}, toon.WithTimeLocation(ny), toon.WithTimeFormatter(func(t time.Time) string {
+		return t.Format("15:04 MST")
+	}))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc, "ts: \"08:00 EDT\"")
+}
+
 func TestMarshalWithIndentOption(t *testing.T) {
 	payload := map[string]any{
 		"outer": map[string]any{
@@ -149,3 +334,438 @@ type stringer string
 func (s stringer) String() string {
 	return string(s)
 }
+
+type status int
+
+const (
+	statusActive status = iota
+	statusPaused
+	statusBlocked
+)
+
+func (s status) String() string {
+	switch s {
+	case statusActive:
+		return "active"
+	case statusPaused:
+		return "paused"
+	case statusBlocked:
+		return "blocked: review"
+	default:
+		return fmt.Sprintf("status(%d)", int(s))
+	}
+}
+
+func (s *status) UnmarshalText(text []byte) error {
+	for candidate := statusActive; candidate <= statusBlocked; candidate++ {
+		if candidate.String() == string(text) {
+			*s = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown status %q", text)
+}
+
+func TestStringerEnumPositions(t *testing.T) {
+	type account struct {
+		ID     int    `toon:"id"`
+		Status status `toon:"status"`
+	}
+
+	t.Run("scalar", func(t *testing.T) {
+		doc, err := toon.MarshalString(account{ID: 1, Status: statusPaused})
+		if err != nil {
+			t.Fatalf("MarshalString: %v", err)
+		}
+		expectLines(t, doc, "id: 1", "status: paused")
+	})
+
+	t.Run("tabular", func(t *testing.T) {
+		doc, err := toon.MarshalString(map[string]any{
+			"accounts": []account{
+				{ID: 1, Status: statusActive},
+				{ID: 2, Status: statusBlocked},
+			},
+		})
+		if err != nil {
+			t.Fatalf("MarshalString: %v", err)
+		}
+		expectLines(t, doc,
+			"accounts[2]{id,status}:",
+			"  1,active",
+			"  2,\"blocked: review\"",
+		)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		doc, err := toon.MarshalString(map[string]any{
+			"statuses": []any{statusActive, []status{statusPaused, statusBlocked}},
+		})
+		if err != nil {
+			t.Fatalf("MarshalString: %v", err)
+		}
+		expectLines(t, doc,
+			"statuses[2]:",
+			"  - active",
+			"  - [2]: paused,\"blocked: review\"",
+		)
+	})
+}
+
+func TestUnmarshalTextUnmarshalerEnum(t *testing.T) {
+	type account struct {
+		ID     int      `toon:"id"`
+		Status status   `toon:"status"`
+		Prior  []status `toon:"prior"`
+	}
+
+	doc := strings.Join([]string{
+		"id: 7",
+		"status: \"blocked: review\"",
+		"prior[2]: active,paused",
+	}, "\n")
+	var decoded account
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if decoded.Status != statusBlocked {
+		t.Fatalf("status = %v", decoded.Status)
+	}
+	if len(decoded.Prior) != 2 || decoded.Prior[0] != statusActive || decoded.Prior[1] != statusPaused {
+		t.Fatalf("prior = %v", decoded.Prior)
+	}
+
+	err := toon.UnmarshalString("status: archived", &decoded)
+	if err == nil || !strings.Contains(err.Error(), `unknown status "archived"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCustomNullLiteral(t *testing.T) {
+	payload := toon.NewObject(
+		toon.Field{Key: "missing", Value: nil},
+		toon.Field{Key: "tilde", Value: "~"},
+		toon.Field{Key: "items", Value: []any{1, nil, "~"}},
+	)
+
+	doc, err := toon.MarshalString(payload, toon.WithNullLiteral("~"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"missing: ~",
+		"tilde: \"~\"",
+		"items[3]: 1,~,\"~\"",
+	)
+
+	root := decodeMap(t, doc, toon.WithDecoderNullLiteral("~"))
+	if root["missing"] != nil || root["tilde"] != "~" {
+		t.Fatalf("unexpected decode: %#v", root)
+	}
+	items := root["items"].([]any)
+	if items[1] != nil || items[2] != "~" {
+		t.Fatalf("unexpected items: %#v", items)
+	}
+
+	if plain := decodeMap(t, doc); plain["missing"] != "~" {
+		t.Fatalf("default decoder should read ~ as a string: %#v", plain)
+	}
+
+	for _, invalid := range []string{"", "true", "0", "a,b", "- x", "a: b"} {
+		doc, err := toon.MarshalString(payload, toon.WithNullLiteral(invalid))
+		if err != nil {
+			t.Fatalf("MarshalString: %v", err)
+		}
+		if !strings.HasPrefix(doc, "missing: null") {
+			t.Fatalf("invalid literal %q should be ignored:\n%s", invalid, doc)
+		}
+	}
+}
+
+func TestAllowNonFinite(t *testing.T) {
+	doc := strings.Join([]string{
+		"nan: NaN",
+		"pos: Infinity",
+		"neg: -Infinity",
+		`quoted: "NaN"`,
+		"values[3]: NaN,1,-Infinity",
+	}, "\n")
+
+	plain := decodeMap(t, doc)
+	if plain["nan"] != "NaN" || plain["pos"] != "Infinity" || plain["neg"] != "-Infinity" {
+		t.Fatalf("non-finite tokens should be strings by default: %#v", plain)
+	}
+
+	root := decodeMap(t, doc, toon.WithAllowNonFinite(true))
+	if f, ok := root["nan"].(float64); !ok || !math.IsNaN(f) {
+		t.Fatalf("expected NaN, got %#v", root["nan"])
+	}
+	if root["pos"] != math.Inf(1) || root["neg"] != math.Inf(-1) {
+		t.Fatalf("expected infinities, got %#v %#v", root["pos"], root["neg"])
+	}
+	if root["quoted"] != "NaN" {
+		t.Fatalf("quoted NaN should stay a string, got %#v", root["quoted"])
+	}
+	values := root["values"].([]any)
+	if f, ok := values[0].(float64); !ok || !math.IsNaN(f) || values[2] != math.Inf(-1) {
+		t.Fatalf("unexpected inline values: %#v", values)
+	}
+
+	var target struct {
+		Pos float64 `toon:"pos"`
+		Neg float32 `toon:"neg"`
+	}
+	if err := toon.UnmarshalString("pos: Infinity\nneg: -Infinity", &target, toon.WithAllowNonFinite(true)); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !math.IsInf(target.Pos, 1) || !math.IsInf(float64(target.Neg), -1) {
+		t.Fatalf("unexpected target: %+v", target)
+	}
+	var count struct {
+		N int `toon:"n"`
+	}
+	if err := toon.UnmarshalString("n: NaN", &count, toon.WithAllowNonFinite(true)); err == nil {
+		t.Fatalf("expected error assigning NaN to int")
+	}
+}
+
+func TestDecodeCaseInsensitiveLiterals(t *testing.T) {
+	doc := strings.Join([]string{
+		"a: True",
+		"b: FALSE",
+		"c: Null",
+		"d: tRuE",
+		`e: "True"`,
+		"flags[3]: TRUE,False,NULL",
+	}, "\n")
+
+	strict := decodeMap(t, doc, toon.WithCaseInsensitiveLiterals(true))
+	if strict["a"] != "True" || strict["b"] != "FALSE" || strict["c"] != "Null" {
+		t.Fatalf("strict mode should keep mixed-case literals as strings: %#v", strict)
+	}
+
+	root := decodeMap(t, doc, toon.WithStrictMode(false), toon.WithCaseInsensitiveLiterals(true))
+	want := map[string]any{
+		"a":     true,
+		"b":     false,
+		"c":     nil,
+		"d":     true,
+		"e":     "True",
+		"flags": []any{true, false, nil},
+	}
+	if !reflect.DeepEqual(root, want) {
+		t.Fatalf("unexpected values: %#v", root)
+	}
+
+	var target struct {
+		On   bool    `toon:"on"`
+		Name *string `toon:"name"`
+	}
+	if err := toon.UnmarshalString("on: TRUE\nname: NULL", &target, toon.WithStrictMode(false), toon.WithCaseInsensitiveLiterals(true)); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !target.On || target.Name != nil {
+		t.Fatalf("unexpected target: %+v", target)
+	}
+}
+
+func TestExtendedNumbers(t *testing.T) {
+	doc := strings.Join([]string{
+		"hex: 0xFF",
+		"oct: 0o17",
+		"bin: -0b1010",
+		"upper: 0XfF",
+		"zero: 0123",
+		"bad: 0xZZ",
+		"under: 0x_1",
+		"bare: 0x",
+		"big: 0x20000000000001",
+	}, "\n")
+
+	plain := decodeMap(t, doc)
+	if plain["hex"] != "0xFF" || plain["bin"] != "-0b1010" {
+		t.Fatalf("prefixed integers should be strings by default: %#v", plain)
+	}
+
+	root := decodeMap(t, doc, toon.WithExtendedNumbers(true))
+	want := map[string]any{
+		"hex":   float64(255),
+		"oct":   float64(15),
+		"bin":   float64(-10),
+		"upper": float64(255),
+		"zero":  "0123",
+		"bad":   "0xZZ",
+		"under": "0x_1",
+		"bare":  "0x",
+		"big":   float64(9007199254740993),
+	}
+	for key, value := range want {
+		if root[key] != value {
+			t.Fatalf("%s: got %#v, want %#v", key, root[key], value)
+		}
+	}
+
+	var target struct {
+		Big  int64 `toon:"big"`
+		Mask uint8 `toon:"mask"`
+	}
+	if err := toon.UnmarshalString("big: 0x20000000000001\nmask: 0b11110000", &target, toon.WithExtendedNumbers(true)); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if target.Big != 9007199254740993 || target.Mask != 0xF0 {
+		t.Fatalf("unexpected target: %+v", target)
+	}
+}
+
+func TestBoolLiterals(t *testing.T) {
+	opt := toon.WithBoolLiterals([]string{"yes", "on", "1"}, []string{"no", "off", "0", "on"})
+	doc := "a: yes\nb: no\nc: on\nd: off\ne: \"yes\"\nf: 1\nflags[3]: yes,no,maybe\nrows[1]{x,y}:\n  yes,off"
+
+	got, err := toon.DecodeString(doc, opt)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	want := map[string]any{
+		"a":     true,
+		"b":     false,
+		"c":     "on",
+		"d":     false,
+		"e":     "yes",
+		"f":     float64(1),
+		"flags": []any{true, false, "maybe"},
+		"rows":  []any{map[string]any{"x": true, "y": false}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected decode: %#v", got)
+	}
+
+	plain, err := toon.DecodeString("a: yes")
+	if err != nil || !reflect.DeepEqual(plain, map[string]any{"a": "yes"}) {
+		t.Fatalf("literals must be opt-in: %#v, %v", plain, err)
+	}
+
+	var flags struct {
+		Enabled bool    `toon:"enabled"`
+		Debug   bool    `toon:"debug"`
+		Count   float64 `toon:"count"`
+	}
+	if err := toon.UnmarshalString("enabled: 1\ndebug: 0\ncount: 1", &flags, opt); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !flags.Enabled || flags.Debug || flags.Count != 1 {
+		t.Fatalf("numeric literals should convert only for bool fields: %+v", flags)
+	}
+	if err := toon.UnmarshalString("enabled: 1", &flags); err == nil {
+		t.Fatalf("expected error assigning a number to bool without literals")
+	}
+}
+
+func TestDecodeUseNumber(t *testing.T) {
+	doc := "amount: 10.50\nid: 12345678901234567890\nnested:\n  rates[2]: 1.0,2e3\nname: x"
+	root := decodeMap(t, doc, toon.WithUseNumber(true))
+	want := map[string]any{
+		"amount": json.Number("10.50"),
+		"id":     json.Number("12345678901234567890"),
+		"nested": map[string]any{"rates": []any{json.Number("1.0"), json.Number("2e3")}},
+		"name":   "x",
+	}
+	if !reflect.DeepEqual(root, want) {
+		t.Fatalf("unexpected value: %#v", root)
+	}
+}
+
+func TestMarshalLossless(t *testing.T) {
+	lossy := map[string]any{
+		"nan":           math.NaN(),
+		"inf":           float32(math.Inf(-1)),
+		"negative zero": math.Copysign(0, -1),
+		"rewritten":     json.Number("1.0"),
+		"exponent":      json.Number("1e3"),
+		"not a number":  json.Number("abc"),
+	}
+	for name, value := range lossy {
+		if _, err := toon.Marshal(map[string]any{"v": value}); err != nil {
+			t.Fatalf("%s: default Marshal should coerce: %v", name, err)
+		}
+		_, err := toon.Marshal(map[string]any{"v": value}, toon.WithLossless(true))
+		if err == nil || !strings.Contains(err.Error(), "lossless") {
+			t.Fatalf("%s: expected lossless error, got %v", name, err)
+		}
+	}
+
+	doc, err := toon.MarshalString(map[string]any{
+		"f":   1.5,
+		"n":   json.Number("2.25"),
+		"z":   0.0,
+		"big": int64(math.MaxInt64),
+	}, toon.WithLossless(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		`big: "9223372036854775807"`,
+		"f: 1.5",
+		"n: 2.25",
+		"z: 0",
+	)
+}
+
+func TestUnmarshalFloat32FromLiteral(t *testing.T) {
+	// The literal lies just above the midpoint between 1 and the next
+	// float32. Rounding through float64 lands on the midpoint, which then
+	// rounds down to 1.
+	var target struct {
+		F32 float32 `toon:"f32"`
+		Exp float32 `toon:"exp"`
+	}
+	if err := toon.UnmarshalString("f32: 1.00000005960464477550\nexp: 1e3", &target); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if want := math.Nextafter32(1, 2); target.F32 != want {
+		t.Fatalf("expected %v, got %v", want, target.F32)
+	}
+	if target.Exp != 1000 {
+		t.Fatalf("expected 1000, got %v", target.Exp)
+	}
+}
+
+func TestNumericBooleans(t *testing.T) {
+	type flag struct {
+		Name string `toon:"name"`
+		On   bool   `toon:"on"`
+	}
+	type payload struct {
+		Enabled bool   `toon:"enabled"`
+		Bits    []bool `toon:"bits"`
+		Flags   []flag `toon:"flags"`
+		Mixed   []any  `toon:"mixed"`
+	}
+	in := payload{
+		Enabled: true,
+		Bits:    []bool{true, false},
+		Flags:   []flag{{Name: "a", On: true}, {Name: "b", On: false}},
+		Mixed:   []any{false, []bool{true}},
+	}
+	doc, err := toon.MarshalString(in, toon.WithNumericBooleans(true))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"enabled: 1",
+		"bits[2]: 1,0",
+		"flags[2]{name,on}:",
+		"  a,1",
+		"  b,0",
+		"mixed[2]:",
+		"  - 0",
+		"  - [1]: 1",
+	)
+
+	var out payload
+	if err := toon.UnmarshalString(doc, &out, toon.WithBoolLiterals([]string{"1"}, []string{"0"})); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if out.Enabled != true || !reflect.DeepEqual(out.Bits, in.Bits) || !reflect.DeepEqual(out.Flags, in.Flags) {
+		t.Fatalf("round trip mismatch: %+v", out)
+	}
+}
@@ -0,0 +1,232 @@
+package toon_test
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/toon-format/toon-go"
+)
+
+var adversarialStrings = []string{
+	"count[2]: x",
+	"[3]: a,b,c",
+	"items[2]{a,b}:",
+	"key: value",
+	"- foo",
+	"-",
+	"--",
+	"-x",
+	"- ",
+	"a,b",
+	"a|b",
+	"a\tb",
+	"{}",
+	"[]",
+	"\"quoted\"",
+	"back\\slash",
+	"line\nbreak",
+	" padded ",
+	"true",
+	"null",
+	"-12",
+	"1e5",
+	"007",
+	"#",
+	"a#b",
+	"x:y",
+	"",
+	"é[1]: ü",
+}
+
+func TestAdversarialStringsRoundTrip(t *testing.T) {
+	delimiters := []toon.Delimiter{toon.DelimiterComma, toon.DelimiterTab, toon.DelimiterPipe}
+	for _, delim := range delimiters {
+		for _, s := range adversarialStrings {
+			payload := toon.NewObject(
+				toon.Field{Key: "value", Value: s},
+				toon.Field{Key: s, Value: "key"},
+				toon.Field{Key: "inline", Value: []string{s, s}},
+				toon.Field{Key: "rows", Value: []toon.Object{
+					toon.NewObject(toon.Field{Key: "a", Value: s}, toon.Field{Key: "b", Value: 1}),
+				}},
+				toon.Field{Key: "list", Value: []any{s, []string{s}, toon.NewObject(toon.Field{Key: "k", Value: s})}},
+			)
+			doc, err := toon.MarshalString(payload,
+				toon.WithDocumentDelimiter(delim),
+				toon.WithArrayDelimiter(delim),
+			)
+			if err != nil {
+				t.Fatalf("%s %q: MarshalString: %v", delim, s, err)
+			}
+			got, err := toon.DecodeString(doc, toon.WithDecoderDocumentDelimiter(delim))
+			if err != nil {
+				t.Fatalf("%s %q: DecodeString: %v\n%s", delim, s, err, doc)
+			}
+			want := map[string]any{
+				"value":  s,
+				s:        "key",
+				"inline": []any{s, s},
+				"rows":   []any{map[string]any{"a": s, "b": float64(1)}},
+				"list":   []any{s, []any{s}, map[string]any{"k": s}},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s %q: round trip mismatch\n got: %#v\nwant: %#v\n%s", delim, s, got, want, doc)
+			}
+
+			root, err := toon.MarshalString(s)
+			if err != nil {
+				t.Fatalf("%q: MarshalString root: %v", s, err)
+			}
+			back, err := toon.DecodeString(root)
+			if err != nil || back != s {
+				t.Fatalf("%q: root round trip got %#v (%v) from %q", s, back, err, root)
+			}
+		}
+	}
+}
+
+func FuzzStringRoundTrip(f *testing.F) {
+	for _, s := range adversarialStrings {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		if !utf8.ValidString(s) {
+			t.Skip()
+		}
+		payload := toon.NewObject(
+			toon.Field{Key: "value", Value: s},
+			toon.Field{Key: "inline", Value: []string{s, s}},
+			toon.Field{Key: "list", Value: []any{s, toon.NewObject(toon.Field{Key: "k", Value: s})}},
+		)
+		doc, err := toon.MarshalString(payload)
+		if err != nil {
+			t.Skip()
+		}
+		got, err := toon.DecodeString(doc)
+		if err != nil {
+			t.Fatalf("DecodeString: %v\n%s", err, doc)
+		}
+		want := map[string]any{
+			"value":  s,
+			"inline": []any{s, s},
+			"list":   []any{s, map[string]any{"k": s}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("round trip mismatch\n got: %#v\nwant: %#v\n%s", got, want, doc)
+		}
+	})
+}
+
+func TestDashPrefixedStringsQuoted(t *testing.T) {
+	doc, err := toon.MarshalString(toon.NewObject(
+		toon.Field{Key: "note", Value: "- bullet"},
+		toon.Field{Key: "dash", Value: "-"},
+		toon.Field{Key: "negative", Value: "-5"},
+		toon.Field{Key: "flag", Value: "-v"},
+		toon.Field{Key: "inner", Value: "a-b"},
+	))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"note: \"- bullet\"",
+		"dash: \"-\"",
+		"negative: \"-5\"",
+		"flag: \"-v\"",
+		"inner: a-b",
+	)
+
+	root := decodeMap(t, doc)
+	if root["note"] != "- bullet" || root["dash"] != "-" || root["negative"] != "-5" || root["flag"] != "-v" {
+		t.Fatalf("unexpected round trip: %#v", root)
+	}
+
+	bare, err := toon.MarshalString("-")
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if bare != "\"-\"" {
+		t.Fatalf("bare dash should be quoted, got %q", bare)
+	}
+}
+
+func TestPublicQuotingHelpers(t *testing.T) {
+	cases := []struct {
+		value     string
+		inArray   bool
+		delimiter toon.Delimiter
+		want      bool
+	}{
+		{"plain", false, toon.DelimiterComma, false},
+		{"a,b", false, toon.DelimiterComma, true},
+		{"a,b", true, toon.DelimiterPipe, false},
+		{"a|b", true, toon.DelimiterPipe, true},
+		{"a|b", false, toon.DelimiterComma, false},
+		{"42", true, toon.DelimiterComma, true},
+		{"- item", false, toon.DelimiterComma, true},
+	}
+	for _, tc := range cases {
+		if got := toon.NeedsQuoting(tc.value, tc.inArray, tc.delimiter); got != tc.want {
+			t.Fatalf("NeedsQuoting(%q, %v, %s) = %v, want %v", tc.value, tc.inArray, tc.delimiter, got, tc.want)
+		}
+	}
+
+	quoted, err := toon.QuoteString("say \"hi\"\n")
+	if err != nil || quoted != `"say \"hi\"\n"` {
+		t.Fatalf("QuoteString = %q, %v", quoted, err)
+	}
+	if _, err := toon.QuoteString("bell\a"); err == nil {
+		t.Fatalf("expected control character error")
+	}
+
+	for key, want := range map[string]string{"name": "name", "user.id": "user.id", "full name": `"full name"`, "": `""`} {
+		got, err := toon.EncodeKey(key)
+		if err != nil || got != want {
+			t.Fatalf("EncodeKey(%q) = %q, %v", key, got, err)
+		}
+	}
+}
+
+func TestDelimiterEscaping(t *testing.T) {
+	payload := toon.NewObject(
+		toon.Field{Key: "tags", Value: []string{"a|b", "c", "x: y|z"}},
+		toon.Field{Key: "rows", Value: []toon.Object{
+			toon.NewObject(toon.Field{Key: "id", Value: 1}, toon.Field{Key: "path", Value: "in|out"}),
+		}},
+		toon.Field{Key: "items", Value: []any{"p|q", toon.NewObject(toon.Field{Key: "k", Value: "v"})}},
+	)
+
+	doc, err := toon.MarshalString(payload,
+		toon.WithArrayDelimiter(toon.DelimiterPipe),
+		toon.WithDelimiterEscaping(true),
+	)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		`tags[3|]: a\|b|c|"x: y|z"`,
+		"rows[1|]{id|path}:",
+		`  1|in\|out`,
+		"items[2|]:",
+		`  - "p|q"`,
+		"  - k: v",
+	)
+
+	got, err := toon.DecodeString(doc, toon.WithDecoderDelimiterEscaping(true))
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	want := map[string]any{
+		"tags":  []any{"a|b", "c", "x: y|z"},
+		"rows":  []any{map[string]any{"id": float64(1), "path": "in|out"}},
+		"items": []any{"p|q", map[string]any{"k": "v"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip mismatch: %#v", got)
+	}
+
+	if _, err := toon.DecodeString(`tags[1]: a\,b`); err == nil {
+		t.Fatalf("escaped delimiters should split without WithDecoderDelimiterEscaping")
+	}
+}
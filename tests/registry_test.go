@@ -0,0 +1,252 @@
+package toon_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+type shape interface {
+	Area() float64
+}
+
+type circle struct {
+	Radius float64 `toon:"radius"`
+}
+
+func (c circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type rect struct {
+	W float64 `toon:"w"`
+	H float64 `toon:"h"`
+}
+
+func (r *rect) Area() float64 { return r.W * r.H }
+
+type label struct {
+	Text string `toon:"text"`
+}
+
+func (l label) String() string { return "label:" + l.Text }
+
+type drawing struct {
+	Title  string  `toon:"title"`
+	Main   shape   `toon:"main"`
+	Shapes []shape `toon:"shapes"`
+}
+
+func init() {
+	toon.RegisterType("circle", circle{})
+	toon.RegisterType("rect", &rect{})
+	toon.RegisterType("label", label{})
+}
+
+func TestUnmarshalRegisteredInterface(t *testing.T) {
+	doc := strings.Join([]string{
+		"title: demo",
+		"main:",
+		"  _type: circle",
+		"  radius: 2",
+		"shapes[2]{_type,w,h}:",
+		"  rect,2,3",
+		"  rect,4,5",
+	}, "\n")
+
+	var d drawing
+	if err := toon.UnmarshalString(doc, &d); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if c, ok := d.Main.(circle); !ok || c.Radius != 2 {
+		t.Fatalf("unexpected main shape: %#v", d.Main)
+	}
+	if len(d.Shapes) != 2 {
+		t.Fatalf("unexpected shapes: %#v", d.Shapes)
+	}
+	if r, ok := d.Shapes[1].(*rect); !ok || r.Area() != 20 {
+		t.Fatalf("unexpected second shape: %#v", d.Shapes[1])
+	}
+}
+
+func TestUnmarshalRegisteredInterfaceCustomKey(t *testing.T) {
+	doc := strings.Join([]string{
+		"main:",
+		"  kind: circle",
+		"  radius: 1",
+	}, "\n")
+	var d drawing
+	if err := toon.UnmarshalString(doc, &d, toon.WithDecoderTypeDiscriminator("kind")); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if _, ok := d.Main.(circle); !ok {
+		t.Fatalf("unexpected main shape: %#v", d.Main)
+	}
+}
+
+func TestUnmarshalRegisteredInterfaceErrors(t *testing.T) {
+	cases := map[string]string{
+		"missing discriminator": "main:\n  radius: 1",
+		"unregistered type":     "main:\n  _type: hexagon",
+		"primitive":             "main: 4",
+	}
+	for name, doc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var d drawing
+			if err := toon.UnmarshalString(doc, &d); err == nil {
+				t.Fatalf("expected error for %q", doc)
+			}
+		})
+	}
+}
+
+func TestRegisterTypeConflicts(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for conflicting registration")
+		}
+	}()
+	toon.RegisterType("circle", rect{})
+}
+
+func TestMarshalTypeDiscriminator(t *testing.T) {
+	d := drawing{
+		Title:  "demo",
+		Main:   circle{Radius: 2},
+		Shapes: []shape{&rect{W: 2, H: 3}, &rect{W: 4, H: 5}},
+	}
+
+	plain, err := toon.MarshalString(d)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if strings.Contains(plain, "_type") {
+		t.Fatalf("discriminator emitted without opt-in: %s", plain)
+	}
+
+	doc, err := toon.MarshalString(d, toon.WithTypeDiscriminator("_type"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"title: demo",
+		"main:",
+		"  _type: circle",
+		"  radius: 2",
+		"shapes[2]{_type,w,h}:",
+		"  rect,2,3",
+		"  rect,4,5",
+	)
+
+	var decoded drawing
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if decoded.Main.Area() != d.Main.Area() || decoded.Shapes[0].Area() != 6 {
+		t.Fatalf("round trip mismatch: %#v", decoded)
+	}
+}
+
+func TestUnmarshalRegisteredInterfaceMap(t *testing.T) {
+	doc := strings.Join([]string{
+		"wheel:",
+		"  _type: circle",
+		"  radius: 1",
+		"door:",
+		"  _type: rect",
+		"  w: 2",
+		"  h: 3",
+		"none: null",
+	}, "\n")
+
+	var shapes map[string]shape
+	if err := toon.UnmarshalString(doc, &shapes); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if len(shapes) != 3 {
+		t.Fatalf("unexpected shapes: %#v", shapes)
+	}
+	if c, ok := shapes["wheel"].(circle); !ok || c.Radius != 1 {
+		t.Fatalf("unexpected wheel: %#v", shapes["wheel"])
+	}
+	if r, ok := shapes["door"].(*rect); !ok || r.Area() != 6 {
+		t.Fatalf("unexpected door: %#v", shapes["door"])
+	}
+	if shapes["none"] != nil {
+		t.Fatalf("expected nil shape, got %#v", shapes["none"])
+	}
+
+	err := toon.UnmarshalString("bad:\n  _type: hexagon", &shapes)
+	if err == nil || !strings.Contains(err.Error(), "bad") || !strings.Contains(err.Error(), `"hexagon" is not registered`) {
+		t.Fatalf("expected unregistered type error naming the key, got %v", err)
+	}
+
+	doc, err = toon.MarshalString(map[string]shape{"wheel": circle{Radius: 1}}, toon.WithTypeDiscriminator("_type"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	var roundTrip map[string]shape
+	if err := toon.UnmarshalString(doc, &roundTrip); err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	if _, ok := roundTrip["wheel"].(circle); !ok {
+		t.Fatalf("unexpected round trip: %#v", roundTrip)
+	}
+}
+
+// Shape is exported so that it can be embedded as a promoted field.
+type Shape interface {
+	Area() float64
+}
+
+func TestMarshalEmbeddedInterface(t *testing.T) {
+	type sprite struct {
+		Shape
+		Name string  `toon:"name"`
+		W    float64 `toon:"w"`
+	}
+
+	doc, err := toon.MarshalString(sprite{Shape: &rect{W: 2, H: 3}, Name: "box", W: 9})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"h: 3",
+		"name: box",
+		"w: 9",
+	)
+
+	doc, err = toon.MarshalString(sprite{Name: "empty"})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"Shape: null",
+		"name: empty",
+		"w: 0",
+	)
+
+	type layer struct {
+		Shape `toon:"shape"`
+	}
+	doc, err = toon.MarshalString(layer{Shape: circle{Radius: 2}}, toon.WithTypeDiscriminator("_type"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	var out layer
+	if err := toon.UnmarshalString(doc, &out); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if c, ok := out.Shape.(circle); !ok || c.Radius != 2 {
+		t.Fatalf("unexpected shape: %#v", out.Shape)
+	}
+}
+
+func TestMarshalRegisteredStringer(t *testing.T) {
+	doc, err := toon.MarshalString(map[string]any{"tag": label{Text: "a"}}, toon.WithTypeDiscriminator("_type"))
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "tag: \"label:a\"" {
+		t.Fatalf("expected the String method to win over the discriminator, got:\n%s", doc)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/toon-format/toon-go"
 )
@@ -311,3 +312,59 @@ func asDelimiter(t *testing.T, value any) toon.Delimiter {
 	}
 	return toon.DelimiterComma
 }
+
+// FuzzRoundTrip decodes arbitrary documents, re-encodes the result and checks
+// that decoding the output yields the same value. Decode fixtures from the
+// spec submodule seed the corpus when it is checked out.
+func FuzzRoundTrip(f *testing.F) {
+	for _, seed := range []string{
+		"a: 1",
+		"price: 1.10",
+		"user:\n  id: 1\n  tags[2]: a,b",
+		"users[2]{id,name}:\n  1,Ada\n  2,Bob",
+		"items[3]:\n  - 1\n  - a: 1\n  - [2]: x,y",
+		"items[1]:\n  - a:\n      b: 1\n    c: 2",
+		"items[2|]: \"a|b\"|c",
+		"\"\": empty key",
+		"[0]:",
+	} {
+		f.Add([]byte(seed))
+	}
+	paths, _ := filepath.Glob(filepath.Join("spec", "tests", "fixtures", "decode", "*.json"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var fixture fixtureFile
+		if json.Unmarshal(data, &fixture) != nil {
+			continue
+		}
+		for _, tc := range fixture.Tests {
+			var input string
+			if json.Unmarshal(tc.Input, &input) == nil {
+				f.Add([]byte(input))
+			}
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if !utf8.Valid(data) {
+			t.Skip()
+		}
+		first, err := toon.Decode(data)
+		if err != nil {
+			t.Skip()
+		}
+		doc, err := toon.Marshal(first)
+		if err != nil {
+			t.Skip()
+		}
+		second, err := toon.Decode(doc)
+		if err != nil {
+			t.Fatalf("Decode of re-encoded document: %v\ninput: %q\noutput: %q", err, data, doc)
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("round trip mismatch\ninput:  %q\noutput: %q\nfirst:  %#v\nsecond: %#v", data, doc, first, second)
+		}
+	})
+}
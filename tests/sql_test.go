@@ -0,0 +1,290 @@
+package toon_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toon-format/toon-go"
+)
+
+// scanRecorder records the value handed to Scan so tests can check the
+// conversions applied to decoded values.
+type scanRecorder struct {
+	got any
+}
+
+func (s *scanRecorder) Scan(src any) error {
+	s.got = src
+	return nil
+}
+
+type upperScanner string
+
+func (u *upperScanner) Scan(src any) error {
+	str, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("unsupported %T", src)
+	}
+	*u = upperScanner(strings.ToUpper(str))
+	return nil
+}
+
+func TestUnmarshalScanner(t *testing.T) {
+	var target struct {
+		Int   scanRecorder   `toon:"int"`
+		Big   scanRecorder   `toon:"big"`
+		Float scanRecorder   `toon:"float"`
+		Str   scanRecorder   `toon:"str"`
+		Bool  scanRecorder   `toon:"bool"`
+		Null  scanRecorder   `toon:"null"`
+		Name  upperScanner   `toon:"name"`
+		Ptr   *upperScanner  `toon:"ptr"`
+		Label sql.NullString `toon:"label"`
+		Count sql.NullInt64  `toon:"count"`
+	}
+	doc := strings.Join([]string{
+		"int: 42",
+		"big: 9007199254740993",
+		"float: 1.5",
+		"str: hi",
+		"bool: true",
+		"null: null",
+		"name: ada",
+		"ptr: bob",
+		"label: null",
+		"count: 7",
+	}, "\n")
+	if err := toon.UnmarshalString(doc, &target); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"int", target.Int.got, int64(42)},
+		{"big", target.Big.got, int64(9007199254740993)},
+		{"float", target.Float.got, 1.5},
+		{"str", target.Str.got, "hi"},
+		{"bool", target.Bool.got, true},
+		{"null", target.Null.got, nil},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Fatalf("%s: Scan received %#v, want %#v", c.name, c.got, c.want)
+		}
+	}
+	if target.Name != "ADA" || target.Ptr == nil || *target.Ptr != "BOB" {
+		t.Fatalf("unexpected scanners: %q %v", target.Name, target.Ptr)
+	}
+	if target.Label.Valid || !target.Count.Valid || target.Count.Int64 != 7 {
+		t.Fatalf("unexpected sql values: %+v %+v", target.Label, target.Count)
+	}
+
+	var bad struct {
+		Name upperScanner `toon:"name"`
+	}
+	if err := toon.UnmarshalString("name: 5", &bad); err == nil || !strings.Contains(err.Error(), "Scan") {
+		t.Fatalf("expected Scan error, got %v", err)
+	}
+}
+
+// celsiusValue implements both driver.Valuer and fmt.Stringer; the Valuer
+// takes precedence.
+type celsiusValue float64
+
+func (c celsiusValue) Value() (driver.Value, error) { return float64(c), nil }
+
+func (c celsiusValue) String() string { return fmt.Sprintf("%.1f°C", float64(c)) }
+
+type failingValuer struct{}
+
+func (failingValuer) Value() (driver.Value, error) { return nil, errors.New("boom") }
+
+func TestMarshalValuer(t *testing.T) {
+	doc, err := toon.MarshalString(struct {
+		Temp    celsiusValue   `toon:"temp"`
+		Name    sql.NullString `toon:"name"`
+		Missing sql.NullString `toon:"missing"`
+		Ptr     *celsiusValue  `toon:"ptr"`
+	}{
+		Temp: 21.5,
+		Name: sql.NullString{String: "Ada", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"temp: 21.5",
+		"name: Ada",
+		"missing: null",
+		"ptr: null",
+	)
+
+	_, err = toon.MarshalString(map[string]any{"v": failingValuer{}})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected Value error, got %v", err)
+	}
+}
+
+func TestSQLNullTypesRoundTrip(t *testing.T) {
+	type row struct {
+		Str     sql.NullString  `toon:"str"`
+		Numeric sql.NullString  `toon:"numeric"`
+		Int     sql.NullInt64   `toon:"int"`
+		BigInt  sql.NullInt64   `toon:"big_int"`
+		Int32   sql.NullInt32   `toon:"int32"`
+		Float   sql.NullFloat64 `toon:"float"`
+		Whole   sql.NullFloat64 `toon:"whole"`
+		Bool    sql.NullBool    `toon:"bool"`
+		Time    sql.NullTime    `toon:"time"`
+	}
+
+	valid := row{
+		Str:     sql.NullString{String: "Ada", Valid: true},
+		Numeric: sql.NullString{String: "123", Valid: true},
+		Int:     sql.NullInt64{Int64: -42, Valid: true},
+		BigInt:  sql.NullInt64{Int64: math.MaxInt64, Valid: true},
+		Int32:   sql.NullInt32{Int32: 7, Valid: true},
+		Float:   sql.NullFloat64{Float64: 2.5, Valid: true},
+		Whole:   sql.NullFloat64{Float64: 3, Valid: true},
+		Bool:    sql.NullBool{Bool: false, Valid: true},
+		Time:    sql.NullTime{Time: time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC), Valid: true},
+	}
+	doc, err := toon.MarshalString(valid)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"str: Ada",
+		`numeric: "123"`,
+		"int: -42",
+		`big_int: "9223372036854775807"`,
+		"int32: 7",
+		"float: 2.5",
+		"whole: 3",
+		"bool: false",
+		`time: "2025-01-02T03:04:05.000000006Z"`,
+	)
+	var decoded row
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if decoded != valid {
+		t.Fatalf("valid round trip mismatch:\n got %+v\nwant %+v", decoded, valid)
+	}
+
+	doc, err = toon.MarshalString(row{})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"str: null",
+		"numeric: null",
+		"int: null",
+		"big_int: null",
+		"int32: null",
+		"float: null",
+		"whole: null",
+		"bool: null",
+		"time: null",
+	)
+	decoded = valid
+	if err := toon.UnmarshalString(doc, &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if decoded != (row{}) {
+		t.Fatalf("null round trip should reset every field, got %+v", decoded)
+	}
+}
+
+// staticDriver serves a fixed result set for every query.
+type staticDriver struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (d *staticDriver) Open(string) (driver.Conn, error) { return staticConn{d}, nil }
+
+type staticConn struct{ d *staticDriver }
+
+func (c staticConn) Prepare(string) (driver.Stmt, error) { return staticStmt(c), nil }
+func (staticConn) Close() error                          { return nil }
+func (staticConn) Begin() (driver.Tx, error)             { return nil, errors.New("unsupported") }
+
+type staticStmt struct{ d *staticDriver }
+
+func (staticStmt) Close() error                               { return nil }
+func (staticStmt) NumInput() int                              { return -1 }
+func (staticStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.New("unsupported") }
+func (s staticStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &staticRows{d: s.d}, nil
+}
+
+type staticRows struct {
+	d   *staticDriver
+	pos int
+}
+
+func (r *staticRows) Columns() []string { return r.d.columns }
+func (*staticRows) Close() error        { return nil }
+func (r *staticRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.d.rows) {
+		return io.EOF
+	}
+	copy(dest, r.d.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func queryStatic(t *testing.T, name string, d *staticDriver) *sql.Rows {
+	t.Helper()
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	t.Cleanup(func() { rows.Close() })
+	return rows
+}
+
+func TestMarshalRows(t *testing.T) {
+	rows := queryStatic(t, "toon-static-rows", &staticDriver{
+		columns: []string{"id", "name", "note", "score"},
+		rows: [][]driver.Value{
+			{int64(1), []byte("Ada"), nil, 1.5},
+			{int64(2), []byte("Bob"), "x,y", 2.0},
+		},
+	})
+	doc, err := toon.MarshalRows(rows)
+	if err != nil {
+		t.Fatalf("MarshalRows: %v", err)
+	}
+	expectLines(t, string(doc),
+		"[2]{id,name,note,score}:",
+		"  1,Ada,null,1.5",
+		"  2,Bob,\"x,y\",2",
+	)
+
+	empty := queryStatic(t, "toon-static-empty", &staticDriver{columns: []string{"id"}})
+	doc, err = toon.MarshalRows(empty)
+	if err != nil {
+		t.Fatalf("MarshalRows: %v", err)
+	}
+	if string(doc) != "[0]:" {
+		t.Fatalf("unexpected empty result: %q", doc)
+	}
+}
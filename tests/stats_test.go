@@ -0,0 +1,53 @@
+package toon_test
+
+import (
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+func TestMarshalWithStats(t *testing.T) {
+	payload := usersPayload{
+		Users: []profile{
+			{ID: 1, Name: "Ada", Active: true},
+			{ID: 2, Name: "Bob", Active: false},
+		},
+		Count: 2,
+	}
+
+	data, stats, err := toon.MarshalWithStats(payload)
+	if err != nil {
+		t.Fatalf("MarshalWithStats: %v", err)
+	}
+	plain, err := toon.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != string(plain) {
+		t.Fatalf("output differs from Marshal:\n%s\n%s", data, plain)
+	}
+	want := toon.Stats{
+		Bytes:           len(plain),
+		Lines:           4,
+		TabularArrays:   1,
+		EstimatedTokens: toon.EstimateTokens(string(plain)),
+	}
+	if stats != want {
+		t.Fatalf("stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	cases := map[string]int{
+		"":          0,
+		"a":         1,
+		"abcd":      1,
+		"abcde":     2,
+		"héllo wör": 3,
+	}
+	for in, want := range cases {
+		if got := toon.EstimateTokens(in); got != want {
+			t.Fatalf("EstimateTokens(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package toon_test
 
 import (
+	"fmt"
+	"math"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/toon-format/toon-go"
 )
@@ -106,3 +110,715 @@ func TestPointerOmitEmptyRoundTrip(t *testing.T) {
 		t.Fatalf("age decode mismatch: %#v", decoded.Age)
 	}
 }
+
+func TestMarshalOmitEmptyKinds(t *testing.T) {
+	type inner struct {
+		A int    `toon:"a"`
+		B string `toon:"b"`
+	}
+	type payload struct {
+		Keep    string            `toon:"keep"`
+		Str     string            `toon:"str,omitempty"`
+		Int     int               `toon:"int,omitempty"`
+		Uint    uint8             `toon:"uint,omitempty"`
+		Float   float64           `toon:"float,omitempty"`
+		Bool    bool              `toon:"bool,omitempty"`
+		Ptr     *int              `toon:"ptr,omitempty"`
+		Any     any               `toon:"any,omitempty"`
+		TypedIf any               `toon:"typed_if,omitempty"`
+		NilMap  map[string]int    `toon:"nil_map,omitempty"`
+		MapVal  map[string]int    `toon:"map_val,omitempty"`
+		NilList []string          `toon:"nil_list,omitempty"`
+		List    []string          `toon:"list,omitempty"`
+		Array   [0]int            `toon:"array,omitempty"`
+		Struct  inner             `toon:"struct,omitempty"`
+		Time    time.Time         `toon:"time,omitempty"`
+		Nested  map[string]string `toon:"nested"`
+	}
+
+	doc, err := toon.MarshalString(payload{
+		Keep:    "yes",
+		TypedIf: (*int)(nil),
+		MapVal:  map[string]int{},
+		List:    []string{},
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "keep: yes\nnested:" {
+		t.Fatalf("empty fields should be dropped, got:\n%s", doc)
+	}
+
+	zero := 0
+	doc, err = toon.MarshalString(payload{
+		Ptr:    &zero,
+		Any:    0,
+		MapVal: map[string]int{"n": 0},
+		List:   []string{""},
+		Struct: inner{B: "x"},
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	lines := strings.Split(doc, "\n")
+	for _, want := range []string{"ptr: 0", "any: 0", "  n: 0", "list[1]: \"\"", "  b: x"} {
+		if !containsLine(lines, want) {
+			t.Fatalf("expected line %q in:\n%s", want, doc)
+		}
+	}
+}
+
+type celsius float64
+
+func (c celsius) String() string { return "warm" }
+
+func TestMarshalTypedNilInInterface(t *testing.T) {
+	doc, err := toon.MarshalString(map[string]any{"x": (*int)(nil)})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "x: null" {
+		t.Fatalf("unexpected doc: %q", doc)
+	}
+
+	type envelope struct {
+		Data  any          `toon:"data"`
+		Temp  fmt.Stringer `toon:"temp"`
+		Items []any        `toon:"items"`
+	}
+	doc, err = toon.MarshalString(envelope{
+		Data:  (*profile)(nil),
+		Temp:  (*celsius)(nil),
+		Items: []any{(*string)(nil), 1},
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"data: null",
+		"temp: null",
+		"items[2]: null,1",
+	)
+}
+
+func TestUnmarshalMapOfTypedValues(t *testing.T) {
+	t.Run("slices of structs", func(t *testing.T) {
+		doc := strings.Join([]string{
+			"admins[2]{id,name,active}:",
+			"  1,Ada,true",
+			"  2,Bob,false",
+			"guests[1]:",
+			"  - id: 3",
+			"    name: Cy",
+			"    active: true",
+		}, "\n")
+		var groups map[string][]profile
+		if err := toon.UnmarshalString(doc, &groups); err != nil {
+			t.Fatalf("UnmarshalString: %v", err)
+		}
+		if len(groups["admins"]) != 2 || groups["admins"][1].Name != "Bob" {
+			t.Fatalf("unexpected admins: %#v", groups["admins"])
+		}
+		if len(groups["guests"]) != 1 || groups["guests"][0].ID != 3 {
+			t.Fatalf("unexpected guests: %#v", groups["guests"])
+		}
+	})
+
+	t.Run("nested structs", func(t *testing.T) {
+		doc := strings.Join([]string{
+			"east:",
+			"  users[1]{id,name,active}:",
+			"    1,Ada,true",
+			"  count: 1",
+			"west:",
+			"  users[0]:",
+			"  count: 0",
+		}, "\n")
+		var regions map[string]usersPayload
+		if err := toon.UnmarshalString(doc, &regions); err != nil {
+			t.Fatalf("UnmarshalString: %v", err)
+		}
+		if regions["east"].Count != 1 || regions["east"].Users[0].Name != "Ada" {
+			t.Fatalf("unexpected east: %#v", regions["east"])
+		}
+		if _, ok := regions["west"]; !ok || len(regions["west"].Users) != 0 {
+			t.Fatalf("unexpected west: %#v", regions["west"])
+		}
+	})
+
+	t.Run("error path includes key", func(t *testing.T) {
+		doc := strings.Join([]string{
+			"b:",
+			"  count: many",
+			"a:",
+			"  count: lots",
+		}, "\n")
+		var regions map[string]usersPayload
+		err := toon.UnmarshalString(doc, &regions)
+		if err == nil || !strings.HasPrefix(err.Error(), "a: count: ") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestUnmarshalUnknownFields(t *testing.T) {
+	doc := strings.Join([]string{
+		"users[2]{id,name,active,role}:",
+		"  1,Ada,true,admin",
+		"  2,Bob,false,user",
+		"count: 2",
+		"source: import",
+	}, "\n")
+
+	type unknown struct {
+		path, key string
+		value     any
+	}
+	var seen []unknown
+	handler := toon.WithUnknownFieldHandler(func(path, key string, value any) {
+		seen = append(seen, unknown{path, key, value})
+	})
+
+	var payload usersPayload
+	if err := toon.UnmarshalString(doc, &payload, handler); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	want := []unknown{
+		{"users[0]", "role", "admin"},
+		{"users[1]", "role", "user"},
+		{"", "source", "import"},
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Fatalf("unexpected unknown fields: %#v", seen)
+	}
+	if payload.Count != 2 || payload.Users[1].Name != "Bob" {
+		t.Fatalf("known fields not decoded: %#v", payload)
+	}
+
+	seen = nil
+	err := toon.UnmarshalString(doc, &payload, handler, toon.WithDisallowUnknownFields(true))
+	if err == nil || err.Error() != `users: index 0: toon: unknown field "role"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 1 {
+		t.Fatalf("handler should run before the error: %#v", seen)
+	}
+}
+
+func TestDecodeFieldsReportsPresentPaths(t *testing.T) {
+	type address struct {
+		City string `toon:"city"`
+		Zip  string `toon:"zip"`
+	}
+	type item struct {
+		ID  int    `toon:"id"`
+		Tag string `toon:"tag"`
+	}
+	type account struct {
+		Name    string         `toon:"name"`
+		Email   string         `toon:"email"`
+		Address address        `toon:"address"`
+		Items   []item         `toon:"items"`
+		Labels  map[string]int `toon:"labels"`
+	}
+
+	current := account{
+		Name:    "Ada",
+		Email:   "ada@example.com",
+		Address: address{City: "London", Zip: "N1"},
+	}
+	doc := strings.Join([]string{
+		"email: ada@lovelace.dev",
+		"address:",
+		"  city: Paris",
+		"items[2]{id,tag}:",
+		"  1,a",
+		"  2,b",
+		"labels:",
+		"  x: 1",
+		"extra: ignored",
+	}, "\n")
+
+	present, err := toon.NewDecoder().DecodeFields([]byte(doc), &current)
+	if err != nil {
+		t.Fatalf("DecodeFields: %v", err)
+	}
+	want := []string{
+		"email",
+		"address",
+		"address.city",
+		"items",
+		"items[0].id",
+		"items[0].tag",
+		"items[1].id",
+		"items[1].tag",
+		"labels",
+	}
+	if !reflect.DeepEqual(present, want) {
+		t.Fatalf("present = %q, want %q", present, want)
+	}
+	if current.Name != "Ada" || current.Address.Zip != "N1" {
+		t.Fatalf("absent fields should be untouched: %+v", current)
+	}
+	if current.Email != "ada@lovelace.dev" || current.Address.City != "Paris" || len(current.Items) != 2 {
+		t.Fatalf("present fields should be assigned: %+v", current)
+	}
+
+	present, err = toon.NewDecoder().DecodeFields([]byte(""), &current)
+	if err != nil || len(present) != 0 || present == nil {
+		t.Fatalf("expected empty non-nil result, got %#v (%v)", present, err)
+	}
+	if _, err := toon.NewDecoder().DecodeFields([]byte("name: x"), current); err == nil {
+		t.Fatalf("expected error for non-pointer target")
+	}
+}
+
+// window reports itself zero when it has no width, regardless of position.
+type window struct {
+	Pos   int
+	Width int
+}
+
+func (w window) IsZero() bool { return w.Width == 0 }
+
+func TestMarshalOmitZero(t *testing.T) {
+	type payload struct {
+		Count   int            `toon:"count,omitzero"`
+		Flag    bool           `toon:"flag,omitzero"`
+		Name    string         `toon:"name,omitzero"`
+		List    []int          `toon:"list,omitzero"`
+		Map     map[string]int `toon:"map,omitzero"`
+		Ptr     *int           `toon:"ptr,omitzero"`
+		Time    time.Time      `toon:"time,omitzero"`
+		Window  window         `toon:"window,omitzero"`
+		WinPtr  *window        `toon:"win_ptr,omitzero"`
+		Both    []int          `toon:"both,omitempty,omitzero"`
+		Visible int            `toon:"visible"`
+	}
+
+	doc, err := toon.MarshalString(payload{Window: window{Pos: 3}})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != "visible: 0" {
+		t.Fatalf("zero fields should be dropped, got:\n%s", doc)
+	}
+
+	zero := 0
+	doc, err = toon.MarshalString(payload{
+		List:   []int{},
+		Map:    map[string]int{},
+		Ptr:    &zero,
+		Window: window{Width: 2},
+		WinPtr: &window{Pos: 1},
+		Both:   []int{},
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"list[0]:",
+		"map:",
+		"ptr: 0",
+		"window:",
+		"  Pos: 0",
+		"  Width: 2",
+		"visible: 0",
+	)
+}
+
+type (
+	orderState string
+	enabled    bool
+	ratio      float64
+	weight     float32
+)
+
+func TestNamedScalarFieldsRoundTrip(t *testing.T) {
+	type settings struct {
+		State   orderState `toon:"state"`
+		Enabled enabled    `toon:"enabled"`
+		Ratio   ratio      `toon:"ratio"`
+		Weight  weight     `toon:"weight"`
+		Quoted  orderState `toon:"quoted"`
+	}
+	want := settings{State: "shipped", Enabled: true, Ratio: 0.25, Weight: 1.5, Quoted: "true"}
+
+	doc, err := toon.MarshalString(want)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"state: shipped",
+		"enabled: true",
+		"ratio: 0.25",
+		"weight: 1.5",
+		`quoted: "true"`,
+	)
+
+	var got settings
+	if err := toon.UnmarshalString(doc, &got); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if got != want {
+		t.Fatalf("round trip mismatch: got %+v want %+v", got, want)
+	}
+
+	doc, err = toon.MarshalString(map[string]any{
+		"states": []orderState{"new", "done"},
+		"nan":    ratio(math.NaN()),
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"nan: null",
+		"states[2]: new,done",
+	)
+}
+
+func TestNamedScalarPointersRoundTrip(t *testing.T) {
+	type record struct {
+		State  *orderState `toon:"state"`
+		On     *enabled    `toon:"on"`
+		Ratio  *ratio      `toon:"ratio"`
+		ID     *bigID      `toon:"id"`
+		Absent *orderState `toon:"absent,omitempty"`
+		Null   *ratio      `toon:"null"`
+	}
+	state := orderState("pending")
+	on := enabled(false)
+	r := ratio(-2.5)
+	id := bigID(9007199254740993)
+	want := record{State: &state, On: &on, Ratio: &r, ID: &id}
+
+	doc, err := toon.MarshalString(want)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"state: pending",
+		"on: false",
+		"ratio: -2.5",
+		`id: "9007199254740993"`,
+		"null: null",
+	)
+
+	var got record
+	if err := toon.UnmarshalString(doc, &got); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if got.State == nil || *got.State != state || got.On == nil || *got.On != on ||
+		got.Ratio == nil || *got.Ratio != r || got.ID == nil || *got.ID != id {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+	if got.Absent != nil || got.Null != nil {
+		t.Fatalf("absent and null pointers should stay nil: %+v", got)
+	}
+}
+
+func TestUnmarshalPointerSliceElements(t *testing.T) {
+	var list struct {
+		Users []*profile `toon:"users"`
+	}
+	doc := "users[3]:\n  - id: 1\n    name: Ada\n  - null\n  - id: 3\n    name: Cy"
+	if err := toon.UnmarshalString(doc, &list); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if len(list.Users) != 3 || list.Users[0] == nil || list.Users[0].Name != "Ada" ||
+		list.Users[1] != nil || list.Users[2] == nil || list.Users[2].ID != 3 {
+		t.Fatalf("unexpected users: %+v", list.Users)
+	}
+
+	var table struct {
+		Users []*profile `toon:"users"`
+	}
+	if err := toon.UnmarshalString("users[2]{id,name,active}:\n  1,Ada,true\n  2,Bob,false", &table); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if len(table.Users) != 2 || table.Users[1] == nil || table.Users[1].Name != "Bob" {
+		t.Fatalf("unexpected tabular users: %+v", table.Users)
+	}
+}
+
+func TestUnmarshalMultiLevelPointers(t *testing.T) {
+	type config struct {
+		Port int `toon:"port"`
+	}
+	type settings struct {
+		Config **config `toon:"config"`
+		Limit  *int     `toon:"limit"`
+	}
+
+	var fresh settings
+	if err := toon.UnmarshalString("config:\n  port: 8080\nlimit: 3", &fresh); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if fresh.Config == nil || *fresh.Config == nil || (*fresh.Config).Port != 8080 {
+		t.Fatalf("config not allocated: %+v", fresh)
+	}
+
+	existing := &config{Port: 1}
+	reused := settings{Config: &existing}
+	if err := toon.UnmarshalString("config:\n  port: 9090", &reused); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if existing.Port != 9090 {
+		t.Fatalf("existing config should be updated in place, got %+v", existing)
+	}
+
+	limit := 5
+	cleared := settings{Config: &existing, Limit: &limit}
+	if err := toon.UnmarshalString("config: null\nlimit: null", &cleared); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if cleared.Config != nil || cleared.Limit != nil {
+		t.Fatalf("null should nil pointer fields: %+v", cleared)
+	}
+}
+
+func TestUnmarshalFieldAliases(t *testing.T) {
+	type event struct {
+		CreatedAt string `toon:"created_at" toonalias:"createdAt,created"`
+		Name      string `toon:"name" toonalias:"id"`
+		ID        int    `toon:"id"`
+	}
+
+	for _, doc := range []string{
+		"created_at: 2024-01-01",
+		"createdAt: 2024-01-01",
+		"created: 2024-01-01",
+	} {
+		var got event
+		if err := toon.UnmarshalString(doc, &got, toon.WithDisallowUnknownFields(true)); err != nil {
+			t.Fatalf("%q: UnmarshalString: %v", doc, err)
+		}
+		if got.CreatedAt != "2024-01-01" {
+			t.Fatalf("%q: alias not applied: %+v", doc, got)
+		}
+	}
+
+	var got event
+	if err := toon.UnmarshalString("name: Ada\nid: 7", &got); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if got.Name != "Ada" || got.ID != 7 {
+		t.Fatalf("alias should not shadow a primary name: %+v", got)
+	}
+
+	doc, err := toon.MarshalString(event{CreatedAt: "2024-01-01", Name: "Ada", ID: 7})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"created_at: 2024-01-01",
+		"name: Ada",
+		"id: 7",
+	)
+}
+
+func TestMarshalFloatPrecisionTag(t *testing.T) {
+	type telemetry struct {
+		Samples []float64 `toon:"samples,prec=2"`
+		Mean    float64   `toon:"mean,prec=1"`
+		Raw     []float64 `toon:"raw"`
+	}
+
+	doc, err := toon.MarshalString(telemetry{
+		Samples: []float64{1.23456, 2.5, -0.001, 3},
+		Mean:    2.06,
+		Raw:     []float64{1.23456},
+	})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"samples[4]: 1.23,2.5,0,3",
+		"mean: 2.1",
+		"raw[1]: 1.23456",
+	)
+}
+
+func TestMarshalRepeatedTagOptionUsesFirst(t *testing.T) {
+	// Each field builds its options separately, so an unordered lookup would
+	// be unlikely to pick the first prec= for all of them.
+	type reading struct {
+		A float64 `toon:"a,prec=1,prec=3"`
+		B float64 `toon:"b,prec=1,prec=3"`
+		C float64 `toon:"c,prec=1,prec=3"`
+		D float64 `toon:"d,prec=1,prec=3"`
+		E float64 `toon:"e,prec=1,prec=3"`
+		F float64 `toon:"f,prec=1,prec=3"`
+		G float64 `toon:"g,prec=1,prec=3"`
+		H float64 `toon:"h,prec=1,prec=3"`
+	}
+
+	doc, err := toon.MarshalString(reading{1.2345, 1.2345, 1.2345, 1.2345, 1.2345, 1.2345, 1.2345, 1.2345})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"a: 1.2", "b: 1.2", "c: 1.2", "d: 1.2",
+		"e: 1.2", "f: 1.2", "g: 1.2", "h: 1.2",
+	)
+}
+
+type money struct {
+	currency string `toon:"currency,method=Currency"`
+	cents    int64  `toon:"cents,method=Cents"`
+	note     string `toon:"note,omitempty,method=Note"`
+	hidden   string
+}
+
+func (m money) Currency() string { return m.currency }
+func (m *money) Cents() int64    { return m.cents }
+func (m money) Note() string     { return m.note }
+
+type badAccessor struct {
+	id int `toon:"id,method=ID"`
+}
+
+func (b badAccessor) ID(prefix string) int { return b.id }
+
+func TestMarshalMethodTag(t *testing.T) {
+	doc, err := toon.MarshalString(money{currency: "EUR", cents: 1050, hidden: "x"})
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"currency: EUR",
+		"cents: 1050",
+	)
+
+	_, err = toon.MarshalString(badAccessor{id: 1})
+	if err == nil || !strings.Contains(err.Error(), "must take no arguments") {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+
+	type missing struct {
+		id int `toon:"id,method=ID"`
+	}
+	_, err = toon.MarshalString(missing{id: 1})
+	if err == nil || !strings.Contains(err.Error(), "method ID not found") {
+		t.Fatalf("expected missing method error, got %v", err)
+	}
+
+	var decoded money
+	if err := toon.UnmarshalString("currency: USD", &decoded); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if decoded.currency != "" {
+		t.Fatalf("accessor fields must not be decoded, got %#v", decoded)
+	}
+}
+
+func TestRootArrayField(t *testing.T) {
+	type item struct {
+		ID   int    `toon:"id"`
+		Name string `toon:"name"`
+	}
+	type inventory struct {
+		Items []item `toon:",root"`
+	}
+
+	in := inventory{Items: []item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}}
+	doc, err := toon.MarshalString(in)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	direct, err := toon.MarshalString(in.Items)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	if doc != direct {
+		t.Fatalf("root field should match the bare slice:\n%s\n%s", doc, direct)
+	}
+	expectLines(t, doc,
+		"[2]{id,name}:",
+		"  1,a",
+		"  2,b",
+	)
+
+	var out inventory
+	if err := toon.UnmarshalString(doc, &out); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("round trip mismatch: %+v", out)
+	}
+
+	type extra struct {
+		Items []item `toon:",root"`
+		Count int    `toon:"count"`
+	}
+	if _, err := toon.MarshalString(extra{}); err == nil || !strings.Contains(err.Error(), "must be the only field") {
+		t.Fatalf("expected only-field error, got %v", err)
+	}
+	if err := toon.UnmarshalString("[0]:", &extra{}); err == nil || !strings.Contains(err.Error(), "must be the only field") {
+		t.Fatalf("expected only-field error on decode, got %v", err)
+	}
+
+	type scalar struct {
+		N int `toon:",root"`
+	}
+	if _, err := toon.MarshalString(scalar{}); err == nil || !strings.Contains(err.Error(), "must be a slice or array") {
+		t.Fatalf("expected kind error, got %v", err)
+	}
+}
+
+func TestUnmarshalRootKey(t *testing.T) {
+	type item struct {
+		ID   int    `toon:"id"`
+		Name string `toon:"name"`
+	}
+	doc := "count: 2\nitems[2]{id,name}:\n  1,a\n  2,b"
+
+	var items []item
+	if err := toon.UnmarshalString(doc, &items, toon.WithRootKey("items")); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(items, []item{{1, "a"}, {2, "b"}}) {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+
+	if err := toon.UnmarshalString(doc, &items, toon.WithRootKey("missing")); err == nil || !strings.Contains(err.Error(), `root key "missing" not found`) {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+	if err := toon.UnmarshalString("[1]: 1", &items, toon.WithRootKey("items")); err == nil || !strings.Contains(err.Error(), "root is an array") {
+		t.Fatalf("expected root kind error, got %v", err)
+	}
+}
+
+func TestHexByteTag(t *testing.T) {
+	type blob struct {
+		Digest [4]byte `toon:"digest,hex"`
+		ID     []byte  `toon:"id,hex"`
+		Raw    []byte  `toon:"raw"`
+	}
+
+	in := blob{Digest: [4]byte{0xde, 0xad, 0xbe, 0xef}, ID: []byte{0x12, 0x34}, Raw: []byte{1, 2}}
+	doc, err := toon.MarshalString(in)
+	if err != nil {
+		t.Fatalf("MarshalString: %v", err)
+	}
+	expectLines(t, doc,
+		"digest: deadbeef",
+		`id: "1234"`,
+		"raw[2]: 1,2",
+	)
+
+	var out blob
+	if err := toon.UnmarshalString(doc, &out); err != nil {
+		t.Fatalf("UnmarshalString: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("round trip mismatch: %+v", out)
+	}
+
+	if err := toon.UnmarshalString("digest: dead", &out); err == nil || !strings.Contains(err.Error(), "expected 4 bytes, got 2") {
+		t.Fatalf("expected length error, got %v", err)
+	}
+	if err := toon.UnmarshalString("digest: zz", &out); err == nil || !strings.Contains(err.Error(), "invalid hex string") {
+		t.Fatalf("expected hex error, got %v", err)
+	}
+}
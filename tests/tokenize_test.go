@@ -0,0 +1,91 @@
+package toon_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+func TestTokenize(t *testing.T) {
+	doc := strings.Join([]string{
+		"name: demo",
+		"meta:",
+		"  tags[2]: a,b",
+		"",
+		"users[2]{id,name}:",
+		"  1,Ada",
+		"  2,Bob",
+		"events[2]:",
+		"  - ready",
+		"  -",
+	}, "\n")
+
+	lines, err := toon.Tokenize([]byte(doc))
+	if err != nil {
+		t.Fatalf("Tokenize: %v", err)
+	}
+	want := []struct {
+		indent int
+		kind   toon.LineKind
+	}{
+		{0, toon.LineKeyValue},
+		{0, toon.LineKeyValue},
+		{1, toon.LineHeader},
+		{0, toon.LineBlank},
+		{0, toon.LineHeader},
+		{1, toon.LineValue},
+		{1, toon.LineValue},
+		{0, toon.LineHeader},
+		{1, toon.LineListItem},
+		{1, toon.LineListItem},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(want))
+	}
+	for i, line := range lines {
+		if line.Number != i+1 || line.Indent != want[i].indent || line.Kind != want[i].kind {
+			t.Fatalf("line %d: got %+v (%s), want indent %d kind %s", i+1, line, line.Kind, want[i].indent, want[i].kind)
+		}
+	}
+	if lines[2].Content != "tags[2]: a,b" {
+		t.Fatalf("unexpected content: %q", lines[2].Content)
+	}
+
+	if _, err := toon.Tokenize([]byte("a:\n   b: 1")); err == nil {
+		t.Fatalf("expected indentation error")
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	cases := []struct {
+		line   string
+		key    string
+		length int
+		delim  toon.Delimiter
+		fields []string
+	}{
+		{"tags[2]: a,b", "tags", 2, toon.DelimiterComma, nil},
+		{"  users[#3|]{id|name}:", "users", 3, toon.DelimiterPipe, []string{"id", "name"}},
+		{"[0]:", "", 0, toon.DelimiterComma, nil},
+		{"- [2\t]: x\ty", "", 2, toon.DelimiterTab, nil},
+		{`"my key"[1]: v`, "my key", 1, toon.DelimiterComma, nil},
+	}
+	for _, tc := range cases {
+		key, length, delim, fields, ok, err := toon.ParseHeader(tc.line)
+		if err != nil || !ok {
+			t.Fatalf("%q: ok=%v err=%v", tc.line, ok, err)
+		}
+		if key != tc.key || length != tc.length || delim != tc.delim || !reflect.DeepEqual(fields, tc.fields) {
+			t.Fatalf("%q: got %q %d %s %v", tc.line, key, length, delim, fields)
+		}
+	}
+
+	if _, _, _, _, ok, err := toon.ParseHeader("name: Ada"); ok || err != nil {
+		t.Fatalf("plain key-value should not be a header: ok=%v err=%v", ok, err)
+	}
+	if _, _, _, _, ok, err := toon.ParseHeader("items[2: a"); ok || err == nil {
+		t.Fatalf("expected error for malformed header, got ok=%v", ok)
+	}
+}
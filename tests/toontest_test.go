@@ -0,0 +1,38 @@
+package toon_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+	"github.com/toon-format/toon-go/toontest"
+)
+
+// fatalRecorder captures the first Fatalf call instead of stopping the test.
+type fatalRecorder struct {
+	testing.TB
+	msg string
+}
+
+func (r *fatalRecorder) Helper() {}
+
+func (r *fatalRecorder) Fatalf(format string, args ...any) {
+	if r.msg == "" {
+		r.msg = fmt.Sprintf(format, args...)
+	}
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	toontest.AssertRoundTrip(t, []byte("zeta: 1\nalpha:\n  b: x\n  a: true\nrows[2]{id,name}:\n  1,Ada\n  2,Bob\n"))
+	toontest.AssertRoundTrip(t, []byte("items[2|]: a|b\r\n"), toon.WithArrayDelimiter(toon.DelimiterPipe))
+	toontest.AssertRoundTrip(t, []byte("a:\n    b: 1"), toon.WithIndent(4))
+	toontest.AssertRoundTrip(t, []byte("a: 12345678901234567890"))
+	toontest.AssertRoundTrip(t, []byte("pi: 3.14159265358979323846264338327950288"))
+
+	rec := &fatalRecorder{TB: t}
+	toontest.AssertRoundTrip(rec, []byte("a: 1.0\nb: 2"))
+	if !strings.Contains(rec.msg, "line 1") || !strings.Contains(rec.msg, `"a: 1"`) {
+		t.Fatalf("expected drift at line 1, got %q", rec.msg)
+	}
+}
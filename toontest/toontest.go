@@ -0,0 +1,54 @@
+// Package toontest provides helpers for testing code that produces TOON
+// documents. It is separate from package toon so that importing toon does not
+// pull in package testing.
+package toontest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/toon-format/toon-go"
+)
+
+// AssertRoundTrip decodes data, re-encodes it with opts and fails t when the
+// result differs from data. Objects keep their key order, numbers keep their
+// digits beyond float64 precision, and indentation is
+// detected from data, so a document round-trips when it is already in the
+// form the encoder writes with opts: canonical numbers, minimal quoting and
+// the configured indentation and delimiters. Line endings and trailing
+// newlines in data are ignored.
+func AssertRoundTrip(t testing.TB, data []byte, opts ...toon.EncoderOption) {
+	t.Helper()
+	value, err := toon.Decode(data, toon.WithOrderedObjects(true), toon.WithUseNumber(true), toon.WithAutoIndent(true))
+	if err != nil {
+		t.Fatalf("toontest: decode: %v", err)
+	}
+	encoded, err := toon.MarshalString(value, opts...)
+	if err != nil {
+		t.Fatalf("toontest: encode: %v", err)
+	}
+	want := canonicalize(string(data))
+	if encoded == want {
+		return
+	}
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(encoded, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			t.Fatalf("toontest: round trip differs at line %d:\n want: %q\n  got: %q\nFull output:\n%s", i+1, w, g, encoded)
+		}
+	}
+}
+
+// canonicalize normalizes line endings and drops trailing newlines.
+func canonicalize(doc string) string {
+	doc = strings.ReplaceAll(doc, "\r\n", "\n")
+	return strings.TrimRight(doc, "\n")
+}